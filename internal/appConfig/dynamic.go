@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"regexp"
 	"strconv"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 
 	"github.com/aquasecurity/table"
 	"github.com/go-playground/validator/v10"
@@ -12,16 +16,58 @@ import (
 	"github.com/spf13/viper"
 )
 
+var DefaultPHPVersion = "7.4"
+
 var ValidPHPVersions = []string{
 	"7.4",
 	"8.0",
 	"8.1",
 }
 
+var DefaultIPv6Subnet = "fd00:6b61:6e61::/64"
+
+var DefaultPrefix = "kana"
+
+var validImageDigest = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+var DefaultImages = map[string]string{
+	"cli":       "wordpress:cli-php{php}",
+	"database":  "mariadb",
+	"traefik":   "traefik",
+	"wordpress": "wordpress:php{php}",
+}
+
 var ValidTypes = []string{
 	"site",
 	"plugin",
 	"theme",
+	"plugins",
+	"themes",
+}
+
+var ValidRestartModes = []string{
+	"reload",
+	"restart",
+}
+
+var ValidTraefikLogLevels = []string{
+	"DEBUG",
+	"INFO",
+	"WARN",
+	"ERROR",
+}
+
+var ValidTraefikLogFormats = []string{
+	"common",
+	"json",
+}
+
+var ValidRoles = []string{
+	"administrator",
+	"editor",
+	"author",
+	"contributor",
+	"subscriber",
 }
 
 func GetDynamicContent(staticConfig StaticConfig) (*viper.Viper, error) {
@@ -31,10 +77,34 @@ func GetDynamicContent(staticConfig StaticConfig) (*viper.Viper, error) {
 	dynamicConfig.SetDefault("xdebug", false)
 	dynamicConfig.SetDefault("type", "site")
 	dynamicConfig.SetDefault("local", false)
-	dynamicConfig.SetDefault("php", "7.4")
+	dynamicConfig.SetDefault("insecure", false)
+	dynamicConfig.SetDefault("gitignore", true)
+	dynamicConfig.SetDefault("hosts", false)
+	dynamicConfig.SetDefault("hostUser", false)
+	dynamicConfig.SetDefault("restartMode", "reload")
+	dynamicConfig.SetDefault("php", DefaultPHPVersion)
 	dynamicConfig.SetDefault("admin.username", "admin")
 	dynamicConfig.SetDefault("admin.password", "password")
 	dynamicConfig.SetDefault("admin.email", "admin@mykanasite.localhost")
+	dynamicConfig.SetDefault("traefik.dashboard", false)
+	dynamicConfig.SetDefault("traefik.logFormat", "common")
+	dynamicConfig.SetDefault("traefik.logLevel", "INFO")
+	dynamicConfig.SetDefault("traefik.accessLog", false)
+	dynamicConfig.SetDefault("idleTimeout", 60)
+	for name, image := range DefaultImages {
+		dynamicConfig.SetDefault(fmt.Sprintf("images.%s", name), image)
+	}
+
+	dynamicConfig.SetDefault("prefix", DefaultPrefix)
+	dynamicConfig.SetDefault("network.ipv6", false)
+	dynamicConfig.SetDefault("network.ipv6Subnet", DefaultIPv6Subnet)
+	dynamicConfig.SetDefault("network.subnet", "")
+	dynamicConfig.SetDefault("network.gateway", "")
+	dynamicConfig.SetDefault("network.hostIP", "")
+	dynamicConfig.SetDefault("network.proxy", "")
+	dynamicConfig.SetDefault("network.noProxy", "localhost,127.0.0.1,::1,.kana.li")
+	dynamicConfig.SetDefault("network.caBundle", "")
+	dynamicConfig.SetDefault("docker.host", "")
 
 	dynamicConfig.SetConfigName("kana")
 	dynamicConfig.SetConfigType("json")
@@ -44,6 +114,12 @@ func GetDynamicContent(staticConfig StaticConfig) (*viper.Viper, error) {
 	if err != nil {
 		_, ok := err.(viper.ConfigFileNotFoundError)
 		if ok {
+			// The config folder doesn't exist yet on a fresh install
+			err = os.MkdirAll(path.Join(staticConfig.AppDirectory, "config"), 0750)
+			if err != nil {
+				return dynamicConfig, err
+			}
+
 			err = dynamicConfig.SafeWriteConfig()
 			if err != nil {
 				fmt.Println("error 1")
@@ -64,8 +140,45 @@ func GetDynamicContent(staticConfig StaticConfig) (*viper.Viper, error) {
 
 	// Reset default php version if there's an invalid version in the config file
 	if !CheckString(dynamicConfig.GetString("php"), ValidPHPVersions) {
+		console.Warn("Invalid PHP version %q in the app config. Defaulting to PHP %s.", dynamicConfig.GetString("php"), DefaultPHPVersion)
+		changeConfig = true
+		dynamicConfig.Set("php", DefaultPHPVersion)
+	}
+
+	validate := validator.New()
+
+	// Reset the IPv6 subnet if it isn't a valid IPv6 CIDR so the network can still be created
+	if validate.Var(dynamicConfig.GetString("network.ipv6Subnet"), "cidrv6") != nil {
+		console.Warn("Invalid IPv6 subnet %q in the app config. Defaulting to %s.", dynamicConfig.GetString("network.ipv6Subnet"), DefaultIPv6Subnet)
 		changeConfig = true
-		dynamicConfig.Set("php", "7.4")
+		dynamicConfig.Set("network.ipv6Subnet", DefaultIPv6Subnet)
+	}
+
+	// Reset the subnet and gateway if they can't be used so Docker can still choose the network's addresses itself
+	if validate.Var(dynamicConfig.GetString("network.subnet"), "omitempty,cidrv4") != nil {
+		console.Warn("Invalid subnet %q in the app config. Letting Docker choose the network's subnet.", dynamicConfig.GetString("network.subnet"))
+		changeConfig = true
+		dynamicConfig.Set("network.subnet", "")
+	}
+
+	if len(dynamicConfig.GetString("network.gateway")) > 0 && !isGatewayInSubnet(dynamicConfig.GetString("network.gateway"), dynamicConfig.GetString("network.subnet")) {
+		console.Warn("Invalid gateway %q in the app config. It must be an address in network.subnet. Letting Docker choose the network's gateway.", dynamicConfig.GetString("network.gateway"))
+		changeConfig = true
+		dynamicConfig.Set("network.gateway", "")
+	}
+
+	// Reset the host IP if it isn't a valid address so the ports can still be bound
+	if validate.Var(dynamicConfig.GetString("network.hostIP"), "omitempty,ip") != nil {
+		console.Warn("Invalid host IP %q in the app config. Binding ports on all interfaces.", dynamicConfig.GetString("network.hostIP"))
+		changeConfig = true
+		dynamicConfig.Set("network.hostIP", "")
+	}
+
+	// Reset the prefix if it can't be used in container names and labels
+	if validate.Var(dynamicConfig.GetString("prefix"), "required,alphanum,lowercase") != nil {
+		console.Warn("Invalid prefix %q in the app config. Defaulting to %s.", dynamicConfig.GetString("prefix"), DefaultPrefix)
+		changeConfig = true
+		dynamicConfig.Set("prefix", DefaultPrefix)
 	}
 
 	if changeConfig {
@@ -87,8 +200,32 @@ func ListDynamicContent(dynamicConfig *viper.Viper) {
 	t.AddRow("admin.email", dynamicConfig.GetString("admin.email"))
 	t.AddRow("admin.password", dynamicConfig.GetString("admin.password"))
 	t.AddRow("admnin.username", dynamicConfig.GetString("admin.username"))
+	t.AddRow("docker.host", dynamicConfig.GetString("docker.host"))
+	t.AddRow("gitignore", dynamicConfig.GetString("gitignore"))
+	t.AddRow("hostUser", dynamicConfig.GetString("hostUser"))
+	t.AddRow("hosts", dynamicConfig.GetString("hosts"))
+	t.AddRow("idleTimeout", dynamicConfig.GetString("idleTimeout"))
+	t.AddRow("images.cli", dynamicConfig.GetString("images.cli"))
+	t.AddRow("images.database", dynamicConfig.GetString("images.database"))
+	t.AddRow("images.traefik", dynamicConfig.GetString("images.traefik"))
+	t.AddRow("images.wordpress", dynamicConfig.GetString("images.wordpress"))
+	t.AddRow("insecure", dynamicConfig.GetString("insecure"))
 	t.AddRow("local", dynamicConfig.GetString("local"))
+	t.AddRow("network.caBundle", dynamicConfig.GetString("network.caBundle"))
+	t.AddRow("network.gateway", dynamicConfig.GetString("network.gateway"))
+	t.AddRow("network.hostIP", dynamicConfig.GetString("network.hostIP"))
+	t.AddRow("network.ipv6", dynamicConfig.GetString("network.ipv6"))
+	t.AddRow("network.ipv6Subnet", dynamicConfig.GetString("network.ipv6Subnet"))
+	t.AddRow("network.noProxy", dynamicConfig.GetString("network.noProxy"))
+	t.AddRow("network.proxy", dynamicConfig.GetString("network.proxy"))
+	t.AddRow("network.subnet", dynamicConfig.GetString("network.subnet"))
 	t.AddRow("php", dynamicConfig.GetString("php"))
+	t.AddRow("prefix", dynamicConfig.GetString("prefix"))
+	t.AddRow("restartMode", dynamicConfig.GetString("restartMode"))
+	t.AddRow("traefik.accessLog", dynamicConfig.GetString("traefik.accessLog"))
+	t.AddRow("traefik.dashboard", dynamicConfig.GetString("traefik.dashboard"))
+	t.AddRow("traefik.logFormat", dynamicConfig.GetString("traefik.logFormat"))
+	t.AddRow("traefik.logLevel", dynamicConfig.GetString("traefik.logLevel"))
 	t.AddRow("type", dynamicConfig.GetString("type"))
 	t.AddRow("xdebug", dynamicConfig.GetString("xdebug"))
 
@@ -114,7 +251,7 @@ func SetDynamicContent(md *cobra.Command, args []string, dynamicConfig *viper.Vi
 	var err error
 
 	switch args[0] {
-	case "local", "xdebug":
+	case "local", "xdebug", "insecure", "gitignore", "hosts", "hostUser", "traefik.accessLog", "traefik.dashboard", "network.ipv6":
 		err = validate.Var(args[1], "boolean")
 		if err != nil {
 			return err
@@ -133,6 +270,63 @@ func SetDynamicContent(md *cobra.Command, args []string, dynamicConfig *viper.Vi
 		if !CheckString(args[1], ValidTypes) {
 			err = fmt.Errorf("please choose a valid project type")
 		}
+	case "restartMode":
+		if !CheckString(args[1], ValidRestartModes) {
+			err = fmt.Errorf("please choose either \"reload\" or \"restart\"")
+		}
+	case "traefik.logLevel":
+		if !CheckString(args[1], ValidTraefikLogLevels) {
+			err = fmt.Errorf("please choose one of %s", strings.Join(ValidTraefikLogLevels, ", "))
+		}
+	case "traefik.logFormat":
+		if !CheckString(args[1], ValidTraefikLogFormats) {
+			err = fmt.Errorf("please choose either \"common\" or \"json\"")
+		}
+	case "idleTimeout":
+		err = validate.Var(args[1], "numeric")
+		if err != nil {
+			return err
+		}
+		intVal, err := strconv.Atoi(args[1])
+		if err != nil || intVal < 1 {
+			return fmt.Errorf("please enter a whole number of minutes greater than 0")
+		}
+		dynamicConfig.Set(args[0], intVal)
+		return dynamicConfig.WriteConfig()
+	case "images.cli", "images.database", "images.traefik", "images.wordpress":
+		if len(args[1]) == 0 || strings.ContainsAny(args[1], " \t") {
+			err = fmt.Errorf("please enter a valid image name such as \"%s\"", DefaultImages[strings.TrimPrefix(args[0], "images.")])
+		} else if _, digest, pinned := strings.Cut(args[1], "@"); pinned && !validImageDigest.MatchString(digest) {
+			err = fmt.Errorf("please enter a valid digest such as \"sha256:\" followed by 64 hexadecimal characters")
+		}
+	case "prefix":
+		err = validate.Var(args[1], "required,alphanum,lowercase")
+	case "network.ipv6Subnet":
+		err = validate.Var(args[1], "cidrv6")
+	case "network.subnet":
+		err = validate.Var(args[1], "omitempty,cidrv4")
+
+		if err == nil && len(args[1]) > 0 && len(dynamicConfig.GetString("network.gateway")) > 0 && !isGatewayInSubnet(dynamicConfig.GetString("network.gateway"), args[1]) {
+			err = fmt.Errorf("the gateway %s isn't in the subnet %s. Please change network.gateway first", dynamicConfig.GetString("network.gateway"), args[1])
+		}
+	case "network.gateway":
+		err = validate.Var(args[1], "omitempty,ipv4")
+
+		if err == nil && len(args[1]) > 0 && !isGatewayInSubnet(args[1], dynamicConfig.GetString("network.subnet")) {
+			err = fmt.Errorf("please enter an address in the network.subnet setting, which must be set first")
+		}
+	case "network.hostIP":
+		err = validate.Var(args[1], "omitempty,ip")
+	case "network.proxy":
+		err = validate.Var(args[1], "omitempty,url")
+	case "network.noProxy":
+		if strings.ContainsAny(args[1], " \t") {
+			err = fmt.Errorf("please enter a comma separated list of hosts without spaces")
+		}
+	case "network.caBundle":
+		err = validate.Var(args[1], "omitempty,file")
+	case "docker.host":
+		err = validate.Var(args[1], "omitempty,uri")
 	case "admin.email":
 		err = validate.Var(args[1], "email")
 	case "admin.password":
@@ -1,5 +1,13 @@
 package appConfig
 
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
 func CheckString(stringToCheck string, validStrings []string) bool {
 
 	for _, validString := range validStrings {
@@ -10,3 +18,53 @@ func CheckString(stringToCheck string, validStrings []string) bool {
 
 	return false
 }
+
+// GetImage Returns the image set for the given container in the "images" setting, filling in the PHP version for the {php} placeholder
+func GetImage(dynamicConfig *viper.Viper, name, phpVersion string) string {
+
+	image := dynamicConfig.GetString(fmt.Sprintf("images.%s", name))
+	if len(image) == 0 {
+		image = DefaultImages[name]
+	}
+
+	return strings.ReplaceAll(image, "{php}", phpVersion)
+}
+
+// isGatewayInSubnet Returns true if the gateway is an IPv4 address inside the subnet
+func isGatewayInSubnet(gateway, subnet string) bool {
+
+	gatewayIP := net.ParseIP(gateway)
+
+	_, subnetNetwork, err := net.ParseCIDR(subnet)
+	if err != nil || gatewayIP == nil || gatewayIP.To4() == nil {
+		return false
+	}
+
+	return subnetNetwork.Contains(gatewayIP)
+}
+
+// GetPrefix Returns the prefix used for the names of Kana's containers, network and labels
+func GetPrefix(dynamicConfig *viper.Viper) string {
+
+	prefix := dynamicConfig.GetString("prefix")
+	if len(prefix) == 0 {
+		prefix = DefaultPrefix
+	}
+
+	return prefix
+}
+
+// GetContainerName Returns the name of the given container of a site, such as kana_mysite_wordpress
+func GetContainerName(dynamicConfig *viper.Viper, siteName, container string) string {
+	return fmt.Sprintf("%s_%s_%s", GetPrefix(dynamicConfig), siteName, container)
+}
+
+// GetPHPLabel Returns the label that records the PHP version a site's WordPress container was started with
+func GetPHPLabel(dynamicConfig *viper.Viper) string {
+	return fmt.Sprintf("%s.php", GetPrefix(dynamicConfig))
+}
+
+// GetSiteLabel Returns the label that marks a container as belonging to a site
+func GetSiteLabel(dynamicConfig *viper.Viper) string {
+	return fmt.Sprintf("%s.site", GetPrefix(dynamicConfig))
+}
@@ -0,0 +1,82 @@
+package appConfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/spf13/viper"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// NewHTTPClient Returns an HTTP client that uses the proxy and extra CA bundle from the app config.
+// Any extra certificate files given, such as Kana's root certificate, are trusted as well.
+func NewHTTPClient(dynamicConfig *viper.Viper, certFiles ...string) (*http.Client, error) {
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	caBundle := dynamicConfig.GetString("network.caBundle")
+	if len(caBundle) > 0 {
+		certFiles = append(certFiles, caBundle)
+	}
+
+	for _, certFile := range certFiles {
+
+		certs, err := os.ReadFile(certFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if !rootCAs.AppendCertsFromPEM(certs) {
+			return nil, fmt.Errorf("no valid certificates found in %s", certFile)
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	transport.Proxy = http.ProxyFromEnvironment
+
+	proxy := dynamicConfig.GetString("network.proxy")
+	if len(proxy) > 0 {
+
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  proxy,
+			HTTPSProxy: proxy,
+			NoProxy:    dynamicConfig.GetString("network.noProxy"),
+		}).ProxyFunc()
+
+		transport.Proxy = func(request *http.Request) (*url.URL, error) {
+			return proxyFunc(request.URL)
+		}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// GetProxyEnv Returns the environment variables that pass the proxy from the app config to a container
+func GetProxyEnv(dynamicConfig *viper.Viper) []string {
+
+	proxy := dynamicConfig.GetString("network.proxy")
+	if len(proxy) == 0 {
+		return []string{}
+	}
+
+	noProxy := dynamicConfig.GetString("network.noProxy")
+
+	// Tools in the containers differ in which case they read so set both
+	return []string{
+		fmt.Sprintf("HTTP_PROXY=%s", proxy),
+		fmt.Sprintf("HTTPS_PROXY=%s", proxy),
+		fmt.Sprintf("NO_PROXY=%s", noProxy),
+		fmt.Sprintf("http_proxy=%s", proxy),
+		fmt.Sprintf("https_proxy=%s", proxy),
+		fmt.Sprintf("no_proxy=%s", noProxy),
+	}
+}
@@ -0,0 +1,386 @@
+package appConfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/viper"
+)
+
+// ConfigRule Checks the value of a config key as it was decoded from JSON, returning an error describing any problem
+type ConfigRule func(value interface{}) error
+
+type ConfigProblem struct {
+	File    string `json:"file"`
+	Key     string `json:"key,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p ConfigProblem) String() string {
+
+	location := p.File
+
+	if p.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, p.Line)
+	}
+
+	if len(p.Key) > 0 {
+		return fmt.Sprintf("%s: %s: %s", location, p.Key, p.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", location, p.Message)
+}
+
+// BoolRule Requires the value to be true or false
+func BoolRule() ConfigRule {
+	return func(value interface{}) error {
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("must be true or false but is %s", describeValue(value))
+		}
+
+		return nil
+	}
+}
+
+// StringRule Requires the value to be a string passing the given validator tag, if any
+func StringRule(tag string) ConfigRule {
+	return func(value interface{}) error {
+		stringValue, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be a string but is %s", describeValue(value))
+		}
+
+		if len(tag) > 0 && validator.New().Var(stringValue, tag) != nil {
+			return fmt.Errorf("%q is not valid (%s)", stringValue, tag)
+		}
+
+		return nil
+	}
+}
+
+// OneOfRule Requires the value to be one of the given strings
+func OneOfRule(validStrings []string) ConfigRule {
+	return func(value interface{}) error {
+		stringValue, ok := value.(string)
+		if !ok || !CheckString(stringValue, validStrings) {
+			return fmt.Errorf("must be one of %s but is %s", strings.Join(validStrings, ", "), describeValue(value))
+		}
+
+		return nil
+	}
+}
+
+// IntRule Requires the value to be a whole number between min and max
+func IntRule(min, max int) ConfigRule {
+	return func(value interface{}) error {
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) || number < float64(min) || number > float64(max) {
+			return fmt.Errorf("must be a whole number from %d to %d but is %s", min, max, describeValue(value))
+		}
+
+		return nil
+	}
+}
+
+// StringListRule Requires the value to be a list of strings, each of which must be one of validStrings if it is given
+func StringListRule(validStrings []string) ConfigRule {
+	return func(value interface{}) error {
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("must be a list of strings but is %s", describeValue(value))
+		}
+
+		for i, item := range list {
+			stringValue, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("item %d must be a string but is %s", i+1, describeValue(item))
+			}
+
+			if len(validStrings) > 0 && !CheckString(stringValue, validStrings) {
+				return fmt.Errorf("item %d must be one of %s but is %q", i+1, strings.Join(validStrings, ", "), stringValue)
+			}
+		}
+
+		return nil
+	}
+}
+
+// StringMapRule Requires the value to be an object with only string values
+func StringMapRule() ConfigRule {
+	return func(value interface{}) error {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("must be an object of strings but is %s", describeValue(value))
+		}
+
+		for key, item := range object {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("%s must be a string but is %s", key, describeValue(item))
+			}
+		}
+
+		return nil
+	}
+}
+
+// describeValue Returns a short description of a decoded JSON value for error messages
+func describeValue(value interface{}) string {
+
+	switch typedValue := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return fmt.Sprintf("%t", typedValue)
+	case float64:
+		return fmt.Sprintf("%g", typedValue)
+	case string:
+		return fmt.Sprintf("%q", typedValue)
+	case []interface{}:
+		return "a list"
+	default:
+		return "an object"
+	}
+}
+
+// ValidateConfigFile Checks every key in the given JSON config file against the rules, which are keyed by their
+// dotted path. Returns all problems found, including unknown keys, along with the line each key is on.
+func ValidateConfigFile(file string, rules map[string]ConfigRule) ([]ConfigProblem, error) {
+
+	problems := []ConfigProblem{}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return problems, nil
+		}
+
+		return problems, err
+	}
+
+	if len(bytes.TrimSpace(content)) == 0 {
+		return problems, nil
+	}
+
+	config := map[string]interface{}{}
+
+	err = json.Unmarshal(content, &config)
+	if err != nil {
+		problem := ConfigProblem{
+			File:    file,
+			Message: err.Error(),
+		}
+
+		var syntaxError *json.SyntaxError
+		if errors.As(err, &syntaxError) {
+			problem.Line = getLine(content, syntaxError.Offset)
+		}
+
+		return append(problems, problem), nil
+	}
+
+	keyLines := getKeyLines(content)
+
+	// Viper treats keys as case insensitive so the rules are matched the same way
+	lowerRules := make(map[string]ConfigRule)
+	for key, rule := range rules {
+		lowerRules[strings.ToLower(key)] = rule
+	}
+
+	var checkObject func(prefix string, object map[string]interface{})
+
+	checkObject = func(prefix string, object map[string]interface{}) {
+
+		for key, value := range object {
+
+			if len(prefix) > 0 {
+				key = fmt.Sprintf("%s.%s", prefix, key)
+			}
+
+			rule, hasRule := lowerRules[strings.ToLower(key)]
+
+			if hasRule {
+				if err := rule(value); err != nil {
+					problems = append(problems, ConfigProblem{File: file, Key: key, Line: keyLines[key], Message: err.Error()})
+				}
+
+				continue
+			}
+
+			if childObject, ok := value.(map[string]interface{}); ok && hasChildRules(key, lowerRules) {
+				checkObject(key, childObject)
+				continue
+			}
+
+			problems = append(problems, ConfigProblem{File: file, Key: key, Line: keyLines[key], Message: "unknown key"})
+		}
+	}
+
+	checkObject("", config)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	return problems, nil
+}
+
+// ValidateDynamicConfig Checks the app's config file, returning every problem found
+func ValidateDynamicConfig(dynamicConfig *viper.Viper) ([]ConfigProblem, error) {
+	return ValidateDynamicConfigFile(dynamicConfig.ConfigFileUsed())
+}
+
+// ValidateDynamicConfigFile Checks the given file against the rules for the app's config file, returning every problem found
+func ValidateDynamicConfigFile(file string) ([]ConfigProblem, error) {
+
+	rules := map[string]ConfigRule{
+		"admin.email":        StringRule("email"),
+		"admin.password":     StringRule("alphanumunicode"),
+		"admin.username":     StringRule("alpha"),
+		"docker.host":        StringRule("omitempty,uri"),
+		"gitignore":          BoolRule(),
+		"hostUser":           BoolRule(),
+		"hosts":              BoolRule(),
+		"idleTimeout":        IntRule(1, math.MaxInt32),
+		"insecure":           BoolRule(),
+		"local":              BoolRule(),
+		"network.caBundle":   StringRule("omitempty,file"),
+		"network.gateway":    StringRule("omitempty,ipv4"),
+		"network.hostIP":     StringRule("omitempty,ip"),
+		"network.ipv6":       BoolRule(),
+		"network.ipv6Subnet": StringRule("cidrv6"),
+		"network.noProxy":    noProxyRule,
+		"network.proxy":      StringRule("omitempty,url"),
+		"network.subnet":     StringRule("omitempty,cidrv4"),
+		"php":                OneOfRule(ValidPHPVersions),
+		"prefix":             StringRule("required,alphanum,lowercase"),
+		"restartMode":        OneOfRule(ValidRestartModes),
+		"traefik.accessLog":  BoolRule(),
+		"traefik.dashboard":  BoolRule(),
+		"traefik.logFormat":  OneOfRule(ValidTraefikLogFormats),
+		"traefik.logLevel":   OneOfRule(ValidTraefikLogLevels),
+		"type":               OneOfRule(ValidTypes),
+		"xdebug":             BoolRule(),
+	}
+
+	for name := range DefaultImages {
+		rules[fmt.Sprintf("images.%s", name)] = imageRule
+	}
+
+	return ValidateConfigFile(file, rules)
+}
+
+// imageRule Requires the value to be an image name, optionally pinned to a valid digest
+func imageRule(value interface{}) error {
+
+	image, ok := value.(string)
+	if !ok || len(image) == 0 || strings.ContainsAny(image, " \t") {
+		return fmt.Errorf("must be an image name such as \"mariadb\" but is %s", describeValue(value))
+	}
+
+	if _, digest, pinned := strings.Cut(image, "@"); pinned && !validImageDigest.MatchString(digest) {
+		return fmt.Errorf("%q must be pinned to a digest of \"sha256:\" followed by 64 hexadecimal characters", image)
+	}
+
+	return nil
+}
+
+// noProxyRule Requires the value to be a comma separated list of hosts without spaces
+func noProxyRule(value interface{}) error {
+
+	hosts, ok := value.(string)
+	if !ok || strings.ContainsAny(hosts, " \t") {
+		return fmt.Errorf("must be a comma separated list of hosts without spaces but is %s", describeValue(value))
+	}
+
+	return nil
+}
+
+// hasChildRules Returns true if any rule is nested under the given key
+func hasChildRules(key string, rules map[string]ConfigRule) bool {
+
+	prefix := fmt.Sprintf("%s.", strings.ToLower(key))
+
+	for ruleKey := range rules {
+		if strings.HasPrefix(ruleKey, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getKeyLines Returns the line each key of the JSON content is on, keyed by its dotted path
+func getKeyLines(content []byte) map[string]int {
+
+	keyLines := make(map[string]int)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+
+	var walk func(prefix string) error
+
+	walk = func(prefix string) error {
+
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		delim, ok := token.(json.Delim)
+		if !ok {
+			return nil
+		}
+
+		for decoder.More() {
+
+			key := prefix
+
+			if delim == '{' {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+
+				key = fmt.Sprint(keyToken)
+				if len(prefix) > 0 {
+					key = fmt.Sprintf("%s.%s", prefix, key)
+				}
+
+				// Keys in lists of objects share a path so keep the first one
+				if _, ok := keyLines[key]; !ok {
+					keyLines[key] = getLine(content, decoder.InputOffset())
+				}
+			}
+
+			err = walk(key)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Consume the closing delimiter
+		_, err = decoder.Token()
+
+		return err
+	}
+
+	walk("")
+
+	return keyLines
+}
+
+// getLine Returns the line number of the given byte offset in the content
+func getLine(content []byte, offset int64) int {
+
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
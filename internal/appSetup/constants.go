@@ -11,6 +11,10 @@ sniStrict = true
 [[tls.certificates]]
 certFile = "/var/certs/kana.site.pem"
 keyFile = "/var/certs/kana.site.key"
+
+[http.middlewares]
+[http.middlewares.redirect-to-https.redirectScheme]
+scheme = "https"
 `
 TRAEFIK_TOML = `[log]
 level = "INFO"
@@ -25,17 +29,10 @@ filename = "/etc/traefik/dynamic.toml"
 
 [api]
 dashboard = true
-debug = true
-insecure = true
 
 [entryPoints]
 [entryPoints.web]
 address = ":80"
-[entryPoints.web.http]
-[entryPoints.web.http.redirections]
-[entryPoints.web.http.redirections.entryPoint]
-scheme = "https"
-to = "websecure"
 
 [entryPoints.websecure]
 address = ":443"
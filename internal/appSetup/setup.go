@@ -1,17 +1,37 @@
 package appSetup
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path"
 
 	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
 	"github.com/ChrisWiegman/kana-cli/pkg/minica"
+
+	"github.com/spf13/viper"
 )
 
 // EnsureStaticConfigFiles Ensures the application's static config files have been generated and are where they need to be
-func EnsureStaticConfigFiles(staticConfig appConfig.StaticConfig) error {
-	return writeFileArrayToDisk(configFiles, staticConfig.AppDirectory)
+func EnsureStaticConfigFiles(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Viper) error {
+
+	files := make([]File, len(configFiles))
+	copy(files, configFiles)
+
+	// Traefik's Docker provider has to use the same network as the sites' containers or it can't reach them
+	for i := range files {
+		if files[i].Name == "traefik.toml" {
+			files[i].Replacements = []Replacement{
+				{
+					Search:  `network = "kana"`,
+					Replace: fmt.Sprintf(`network = "%s"`, appConfig.GetPrefix(dynamicConfig)),
+					Count:   1,
+				},
+			}
+		}
+	}
+
+	return writeFileArrayToDisk(files, staticConfig.AppDirectory)
 }
 
 // EnsureCerts Ensures SSL certificates have been generated and are where they need to be
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/aquasecurity/table"
+	"github.com/spf13/cobra"
+)
+
+var flagAllPasswords bool
+
+func newAuthCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the sessions and application passwords of the current site's users for testing authentication.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newAuthSessionCommand(site),
+		newAuthAppPasswordCommand(site),
+	)
+
+	return cmd
+}
+
+func newAuthSessionCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "Manage the login sessions of a user.",
+		Args:  cobra.NoArgs,
+	}
+
+	destroyCmd := &cobra.Command{
+		Use:   "destroy <user>",
+		Short: "Log the user out everywhere by destroying all of their sessions.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runAuthSessionDestroy(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.AddCommand(destroyCmd)
+
+	return cmd
+}
+
+func newAuthAppPasswordCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "app-password",
+		Short: "Manage the application passwords a user authenticates REST API requests with.",
+		Args:  cobra.NoArgs,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create <user> <name>",
+		Short: "Create an application password for the user and print it.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runAuthAppPasswordCreate(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(2),
+	}
+
+	createCmd.Flags().BoolVar(&flagJSON, "json", false, "Print the application password and its details as JSON.")
+
+	listCmd := &cobra.Command{
+		Use:   "list <user>",
+		Short: "List the application passwords of the user.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runAuthAppPasswordList(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	listCmd.Flags().BoolVar(&flagJSON, "json", false, "Print the list as JSON.")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <user> [uuid]",
+		Short: "Delete an application password of the user, or all of them with --all-passwords.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runAuthAppPasswordDelete(cmd, args, site)
+		},
+		Args: cobra.RangeArgs(1, 2),
+	}
+
+	// "--all" is taken by the commands that run against every site
+	deleteCmd.Flags().BoolVar(&flagAllPasswords, "all-passwords", false, "Delete all of the user's application passwords.")
+
+	cmd.AddCommand(
+		createCmd,
+		listCmd,
+		deleteCmd,
+	)
+
+	return cmd
+}
+
+func runAuthSessionDestroy(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkAuthSiteRunning(site)
+
+	err := site.DestroySessions(args[0])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Destroyed all sessions of %s", args[0])
+}
+
+func runAuthAppPasswordCreate(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkAuthSiteRunning(site)
+
+	applicationPassword, err := site.CreateApplicationPassword(args[0], args[1])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagJSON {
+		printJSON(applicationPassword)
+		return
+	}
+
+	console.Info("Created the application password %s for %s. Use it with basic authentication, such as 'curl --user \"%s:<password>\" %swp-json/wp/v2/users/me'", applicationPassword.UUID, args[0], args[0], site.GetURL(false))
+
+	// Print the password itself as command output so it's still shown with --quiet
+	fmt.Println(applicationPassword.Password)
+}
+
+func runAuthAppPasswordList(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkAuthSiteRunning(site)
+
+	applicationPasswords, err := site.GetApplicationPasswords(args[0])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagJSON {
+		printJSON(applicationPasswords)
+		return
+	}
+
+	if len(applicationPasswords) == 0 {
+		console.Info("%s has no application passwords.", args[0])
+		return
+	}
+
+	t := table.New(os.Stdout)
+
+	t.SetHeaders("UUID", "Name", "Created", "Last Used", "Last IP")
+
+	for _, applicationPassword := range applicationPasswords {
+		t.AddRow(
+			applicationPassword.UUID,
+			applicationPassword.Name,
+			formatTimestamp(applicationPassword.Created.String()),
+			formatTimestamp(applicationPassword.LastUsed.String()),
+			applicationPassword.LastIP)
+	}
+
+	t.Render()
+}
+
+func runAuthAppPasswordDelete(cmd *cobra.Command, args []string, site *site.Site) {
+
+	if flagAllPasswords == (len(args) == 2) {
+		console.Error(fmt.Errorf("please give either the UUID of the application password to delete or --all-passwords"))
+		os.Exit(1)
+	}
+
+	checkAuthSiteRunning(site)
+
+	uuid := ""
+	if len(args) == 2 {
+		uuid = args[1]
+	}
+
+	err := site.DeleteApplicationPassword(args[0], uuid)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagAllPasswords {
+		console.Info("Deleted all application passwords of %s", args[0])
+	} else {
+		console.Info("Deleted the application password %s", uuid)
+	}
+}
+
+// checkAuthSiteRunning Exits with an error if the site isn't running
+func checkAuthSiteRunning(site *site.Site) {
+
+	ensureSiteRunning(site, "auth")
+}
+
+// formatTimestamp Returns the Unix timestamp as a local date and time, or "never" if it is empty
+func formatTimestamp(timestamp string) string {
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || seconds == 0 {
+		return "never"
+	}
+
+	return time.Unix(seconds, 0).Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagKeep int
+var flagInterval time.Duration
+
+func newBackupCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the database of the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runBackup(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().IntVar(&flagKeep, "keep", 0, "Only keep the newest backups, removing older ones. Set to 0 to keep all backups.")
+	cmd.Flags().DurationVar(&flagInterval, "interval", 0, "Keep running and back up the database at the given interval (for example 30m).")
+
+	cmd.AddCommand(
+		newBackupListCommand(site),
+		newBackupRestoreCommand(site),
+	)
+
+	return cmd
+}
+
+func newBackupListCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the database backups of the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runBackupList(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func newBackupRestoreCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "restore <timestamp>",
+		Short: "Restore a database backup of the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runBackupRestore(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func runBackup(cmd *cobra.Command, args []string, site *site.Site) {
+
+	if flagKeep < 0 {
+		console.Error(fmt.Errorf("the keep flag must be 0 or greater"))
+		os.Exit(1)
+	}
+
+	ensureSiteRunning(site, "backup")
+
+	err := backupSite(site)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagInterval <= 0 {
+		return
+	}
+
+	console.Info("Backing up the database every %s. Press Ctrl+C to stop.", flagInterval)
+
+	for range time.Tick(flagInterval) {
+
+		if !site.IsSiteRunning() {
+			console.Warn("The site isn't running. Skipping this backup.")
+			continue
+		}
+
+		if site.IsSitePaused() {
+			console.Warn("The site is paused. Skipping this backup.")
+			continue
+		}
+
+		err = backupSite(site)
+		if err != nil {
+			console.Error(err)
+		}
+	}
+}
+
+func runBackupList(cmd *cobra.Command, args []string, site *site.Site) {
+
+	backups, err := site.GetBackups()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if len(backups) == 0 {
+		console.Info("The site doesn't have any backups. Run 'kana backup' to create one.")
+		return
+	}
+
+	for _, backup := range backups {
+		fmt.Println(backup)
+	}
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "backup")
+
+	err := site.RestoreBackup(args[0])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Restored the database from the %s backup", args[0])
+}
+
+func backupSite(kanaSite *site.Site) error {
+
+	timestamp, err := kanaSite.BackupDatabase(flagKeep)
+	if err != nil {
+		return err
+	}
+
+	console.Info("Database backed up to %s", timestamp)
+
+	return nil
+}
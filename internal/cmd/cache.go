@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newCacheCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the caches of the current site.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newCacheFlushCommand(site),
+	)
+
+	return cmd
+}
+
+func newCacheFlushCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "flush",
+		Short: "Flush the object cache of the current site, including Redis if it is in use.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runCacheFlush(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func runCacheFlush(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "cache")
+
+	cacheType, err := site.FlushCache()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Flushed the object cache (%s)", cacheType)
+}
@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 	"github.com/ChrisWiegman/kana-cli/internal/site"
 
 	"github.com/spf13/cobra"
@@ -21,6 +27,43 @@ func newConfigCommand(site *site.Site) *cobra.Command {
 		Args: cobra.RangeArgs(0, 2),
 	}
 
+	cmd.AddCommand(newConfigValidateCommand(site))
+	cmd.AddCommand(newConfigEditCommand(site))
+
+	return cmd
+}
+
+var flagGlobal bool
+var flagSite bool
+
+func newConfigEditCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Open the app config, or the site's .kana.json file with --site, in $EDITOR and save it if it is valid.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runConfigEdit(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagGlobal, "global", false, "Edit the app config used by every site. This is the default.")
+	cmd.Flags().BoolVar(&flagSite, "site", false, "Edit the current site's .kana.json file, creating it if needed.")
+
+	return cmd
+}
+
+func newConfigValidateCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the app config and the site's .kana.json file for invalid or unknown settings.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runConfigValidate(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
 	return cmd
 }
 
@@ -34,7 +77,7 @@ func runConfigCommand(cmd *cobra.Command, args []string, site *site.Site) {
 	case 1:
 		value, err := appConfig.GetDynamicContentItem(cmd, args, site.DynamicConfig)
 		if err != nil {
-			fmt.Println(err)
+			console.Error(err)
 			os.Exit(1)
 		}
 
@@ -42,8 +85,180 @@ func runConfigCommand(cmd *cobra.Command, args []string, site *site.Site) {
 	case 2:
 		err := appConfig.SetDynamicContent(cmd, args, site.DynamicConfig)
 		if err != nil {
-			fmt.Println(err)
+			console.Error(err)
 			os.Exit(1)
 		}
 	}
 }
+
+func runConfigValidate(cmd *cobra.Command, args []string, site *site.Site) {
+
+	err := validateConfig(site)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("The config is valid")
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string, kanaSite *site.Site) {
+
+	if flagGlobal && flagSite {
+		console.Error(fmt.Errorf("please use only one of --global or --site"))
+		os.Exit(1)
+	}
+
+	configFile := kanaSite.DynamicConfig.ConfigFileUsed()
+	validate := appConfig.ValidateDynamicConfigFile
+
+	if flagSite {
+		configFile = kanaSite.GetSiteConfigFile()
+		validate = site.ValidateSiteConfigFile
+	}
+
+	if len(configFile) == 0 {
+		console.Error(fmt.Errorf("unable to find the app config. Please run 'kana config' to create it"))
+		os.Exit(1)
+	}
+
+	saved, err := editConfigFile(configFile, validate)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if !saved {
+		console.Warn("The changes were discarded and %s was not changed", configFile)
+		os.Exit(1)
+	}
+
+	console.Info("Saved %s", configFile)
+}
+
+// editConfigFile Opens a copy of the config file in the user's editor, asking them to edit it again until it is valid.
+// The file is only replaced once the copy is valid so a mistake never leaves a broken config behind. Returns false if
+// the user gave up on their changes.
+func editConfigFile(configFile string, validate func(file string) ([]appConfig.ConfigProblem, error)) (bool, error) {
+
+	content, err := os.ReadFile(configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	if len(strings.TrimSpace(string(content))) == 0 {
+		content = []byte("{\n}\n")
+	}
+
+	// The copy keeps the file's name so editors highlight it as JSON
+	tempDirectory, err := os.MkdirTemp("", "kana-config-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(tempDirectory)
+
+	tempFile := filepath.Join(tempDirectory, filepath.Base(configFile))
+
+	err = os.WriteFile(tempFile, content, 0600)
+	if err != nil {
+		return false, err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+
+		err = runEditor(tempFile)
+		if err != nil {
+			return false, err
+		}
+
+		problems, err := validate(tempFile)
+		if err != nil {
+			return false, err
+		}
+
+		if len(problems) == 0 {
+			break
+		}
+
+		for _, problem := range problems {
+			problem.File = configFile
+			console.Warn("%s", problem)
+		}
+
+		fmt.Printf("Found %d problem(s) in the config. Edit it again? [Y/n] ", len(problems))
+
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			// Without a terminal to answer from, the edit would otherwise be repeated forever
+			if err == io.EOF {
+				fmt.Println()
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		answer = strings.ToLower(strings.TrimSpace(answer))
+
+		if answer != "" && answer != "y" && answer != "yes" {
+			return false, nil
+		}
+	}
+
+	editedContent, err := os.ReadFile(tempFile)
+	if err != nil {
+		return false, err
+	}
+
+	return true, os.WriteFile(configFile, editedContent, 0644)
+}
+
+// runEditor Opens the file in the editor set in $VISUAL or $EDITOR, falling back to vi, and waits for it to close
+func runEditor(file string) error {
+
+	editor := os.Getenv("VISUAL")
+
+	if len(editor) == 0 {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if len(editor) == 0 {
+		editor = "vi"
+	}
+
+	// Editors are often set with arguments, such as "code --wait"
+	editorArgs := strings.Fields(editor)
+
+	command := exec.Command(editorArgs[0], append(editorArgs[1:], file)...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	err := command.Run()
+	if err != nil {
+		return fmt.Errorf("unable to run the editor %q. Please set $EDITOR to your editor: %s", editor, err)
+	}
+
+	return nil
+}
+
+// validateConfig Prints every problem found in the app and site config, returning an error if there are any
+func validateConfig(site *site.Site) error {
+
+	problems, err := site.ValidateConfig()
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range problems {
+		console.Warn("%s", problem)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in the config. Please fix them and try again", len(problems))
+	}
+
+	return nil
+}
@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/aquasecurity/table"
+	"github.com/spf13/cobra"
+)
+
+var flagTransients bool
+var flagFromURL string
+var flagHeader string
+var flagJSON bool
+var flagTables []string
+var flagExcludeTables []string
+var flagAnonymize bool
+var flagGzip bool
+
+func newDBCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the database of the current site.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newDBConnectStringCommand(site),
+		newDBExportCommand(site),
+		newDBImportCommand(site),
+		newDBOptimizeCommand(site),
+		newDBQueryCommand(site),
+		newDBSizeCommand(site),
+		newDBTablesCommand(site),
+	)
+
+	return cmd
+}
+
+func newDBConnectStringCommand(kanaSite *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "connect-string",
+		Short: "Print the details for connecting to the database of the current site from your computer.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBConnectString(cmd, args, kanaSite)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&flagFormat, "format", "url", fmt.Sprintf("The format of the connection details. One of %s.", strings.Join(site.DatabaseConnectionFormats, ", ")))
+
+	return cmd
+}
+
+func newDBExportCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export the database of the current site to a .sql or .sql.gz file.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBExport(cmd, args, site)
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	cmd.Flags().StringSliceVar(&flagTables, "tables", []string{}, "A comma-separated list of the only tables to export.")
+	cmd.Flags().StringSliceVar(&flagExcludeTables, "exclude-tables", []string{}, "A comma-separated list of tables to leave out of the export.")
+	cmd.Flags().BoolVar(&flagAnonymize, "anonymize", false, "Replace user emails, names and the other columns in the anonymizeFields option with fake data in the export.")
+	cmd.Flags().BoolVar(&flagGzip, "gzip", false, "Compress the export with gzip, adding .gz to the file name if needed. Files ending in .gz are always compressed.")
+
+	return cmd
+}
+
+func newDBImportCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import a .sql or .sql.gz file into the database of the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBImport(cmd, args, site)
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	cmd.Flags().StringVar(&flagFromURL, "from-url", "", "Download the .sql or .sql.gz file to import from the given URL.")
+	cmd.Flags().StringVar(&flagHeader, "header", "", "A header, such as \"Authorization: Bearer <token>\", to send when downloading with --from-url.")
+
+	return cmd
+}
+
+func newDBOptimizeCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "optimize",
+		Short: "Optimize and repair the database tables of the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBOptimize(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagTransients, "transients", false, "Delete all transients before optimizing the database.")
+
+	return cmd
+}
+
+func newDBQueryCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "query <sql>",
+		Short: "Run SQL statements against the database of the current site and show the results as a table.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBQuery(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Print the results as JSON.")
+
+	return cmd
+}
+
+func newDBSizeCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "size",
+		Short: "Show the size of the database of the current site and each of its tables.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBSize(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Print the sizes, in bytes, as JSON.")
+
+	return cmd
+}
+
+func newDBTablesCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "tables",
+		Short: "List the tables in the database of the current site and the number of rows in each.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBTables(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Print the tables as JSON.")
+
+	return cmd
+}
+
+func runDBExport(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "db")
+
+	exportFile := fmt.Sprintf("%s.sql", site.StaticConfig.SiteName)
+
+	if len(args) == 1 {
+		exportFile = args[0]
+	}
+
+	if flagGzip && !strings.HasSuffix(exportFile, ".gz") {
+		exportFile = fmt.Sprintf("%s.gz", exportFile)
+	}
+
+	var err error
+
+	if flagAnonymize {
+		err = site.ExportAnonymizedDatabase(exportFile, flagTables, flagExcludeTables)
+	} else {
+		err = site.ExportDatabase(exportFile, flagTables, flagExcludeTables)
+	}
+
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Database exported to %s", exportFile)
+}
+
+func runDBImport(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "db")
+
+	if (len(args) == 1) == (len(flagFromURL) > 0) {
+		console.Error(fmt.Errorf("please specify either a file to import or the --from-url flag"))
+		os.Exit(1)
+	}
+
+	var err error
+
+	if len(flagFromURL) > 0 {
+		err = site.ImportDatabaseFromURL(flagFromURL, flagHeader)
+	} else {
+		err = site.ImportDatabase(args[0])
+	}
+
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+}
+
+func runDBOptimize(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "db")
+
+	output, err := site.OptimizeDatabase(flagTransients)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	fmt.Println(output)
+}
+
+func runDBQuery(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "db")
+
+	results, err := site.QueryDatabase(args[0])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagJSON {
+		output, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			console.Error(err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(output))
+		return
+	}
+
+	for _, result := range results {
+
+		// Statements that don't change rows, such as SET, report -1
+		if result.IsWrite && result.AffectedRows < 0 {
+			fmt.Println("Query OK")
+			continue
+		}
+
+		if result.IsWrite {
+			fmt.Printf("Query OK, %d rows affected\n", result.AffectedRows)
+			continue
+		}
+
+		if len(result.Rows) == 0 {
+			fmt.Println("Empty set")
+			continue
+		}
+
+		t := table.New(os.Stdout)
+
+		t.SetHeaders(result.Columns...)
+
+		for _, row := range result.Rows {
+			t.AddRow(row...)
+		}
+
+		t.Render()
+	}
+}
+
+func runDBConnectString(cmd *cobra.Command, args []string, kanaSite *site.Site) {
+
+	ensureSiteRunning(kanaSite, "db")
+
+	connection, err := kanaSite.GetDatabaseConnectionString(flagFormat)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	fmt.Println(connection)
+}
+
+func runDBSize(cmd *cobra.Command, args []string, kanaSite *site.Site) {
+
+	ensureSiteRunning(kanaSite, "db")
+
+	databaseSize, err := kanaSite.GetDatabaseSizes()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagJSON {
+		output, err := json.MarshalIndent(databaseSize, "", "  ")
+		if err != nil {
+			console.Error(err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(output))
+		return
+	}
+
+	t := table.New(os.Stdout)
+
+	t.SetHeaders("Table", "Size")
+
+	for _, tableSize := range databaseSize.Tables {
+		t.AddRow(tableSize.Name, site.FormatBytes(tableSize.Size))
+	}
+
+	t.SetFooters("Total", site.FormatBytes(databaseSize.Total))
+
+	t.Render()
+}
+
+func runDBTables(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "db")
+
+	tables, err := site.GetTables()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagJSON {
+		output, err := json.MarshalIndent(tables, "", "  ")
+		if err != nil {
+			console.Error(err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(output))
+		return
+	}
+
+	t := table.New(os.Stdout)
+
+	t.SetHeaders("Table", "Rows")
+
+	for _, databaseTable := range tables {
+		t.AddRow(databaseTable.Name, strconv.FormatUint(databaseTable.Rows, 10))
+	}
+
+	t.Render()
+}
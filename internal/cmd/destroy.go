@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 	"github.com/ChrisWiegman/kana-cli/internal/site"
 
 	"github.com/spf13/cobra"
@@ -20,22 +21,34 @@ func newDestroyCommand(site *site.Site) *cobra.Command {
 		Args: cobra.NoArgs,
 	}
 
+	cmd.Flags().BoolVar(&flagBackup, "backup", false, "Save a copy of the site's database in Kana's backups folder before destroying the site.")
+
 	return cmd
 }
 
 func runDestroy(cmd *cobra.Command, args []string, site *site.Site) {
 
+	if flagBackup {
+		archiveFile, err := site.ArchiveDatabase()
+		if err != nil {
+			console.Error(fmt.Errorf("unable to back up the database so the site was not destroyed: %s", err))
+			os.Exit(1)
+		}
+
+		console.Info("Database backed up to %s", archiveFile)
+	}
+
 	// Stop the WordPress site.
 	err := site.StopWordPress()
 	if err != nil {
-		fmt.Println(err)
+		console.Error(err)
 		os.Exit(1)
 	}
 
 	// Remove the site's folder in the config directory.
 	err = os.RemoveAll(site.StaticConfig.SiteDirectory)
 	if err != nil {
-		fmt.Println(err)
+		console.Error(err)
 		os.Exit(1)
 	}
 }
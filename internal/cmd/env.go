@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagFormat string
+
+func newEnvCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Print environment variables describing how to connect to the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runEnv(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&flagFormat, "format", "shell", "The output format. Either \"shell\" or \"json\".")
+
+	return cmd
+}
+
+func runEnv(cmd *cobra.Command, args []string, site *site.Site) {
+
+	environment := site.GetEnvironment()
+
+	switch flagFormat {
+	case "shell":
+		for _, variable := range environment {
+			fmt.Printf("export %s='%s'\n", variable.Name, strings.ReplaceAll(variable.Value, "'", `'\''`))
+		}
+
+		fmt.Println("# Run this command to configure your shell:")
+		fmt.Println("# eval \"$(kana env)\"")
+	case "json":
+		values := map[string]string{}
+
+		for _, variable := range environment {
+			values[variable.Name] = variable.Value
+		}
+
+		output, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			console.Error(err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(output))
+	default:
+		console.Error(fmt.Errorf("invalid format %q. Please use \"shell\" or \"json\"", flagFormat))
+		os.Exit(1)
+	}
+}
@@ -1,9 +1,9 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 	"github.com/ChrisWiegman/kana-cli/internal/site"
 
 	"github.com/spf13/cobra"
@@ -17,24 +17,35 @@ func newExportCommand(site *site.Site) *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			runExport(cmd, args, site)
 		},
-		Args: cobra.ArbitraryArgs,
+		Args: cobra.NoArgs,
 	}
 
-	cmd.DisableFlagParsing = true
+	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, "Export the config of all running sites.")
 
 	return cmd
 }
 
 func runExport(cmd *cobra.Command, args []string, site *site.Site) {
 
-	if !site.IsSiteRunning() {
-		fmt.Println("The export command only works on a running site.  Please run 'kana start' to start the site.")
-		os.Exit(1)
+	if flagAll {
+		runOnAllSites(site, exportSite)
+		return
 	}
 
+	ensureSiteRunning(site, "export")
+
 	err := site.ExportSiteConfig()
 	if err != nil {
-		fmt.Println(err)
+		console.Error(err)
 		os.Exit(1)
 	}
 }
+
+func exportSite(kanaSite *site.Site) error {
+
+	if !kanaSite.IsSiteRunning() {
+		return errSiteSkipped("not running")
+	}
+
+	return kanaSite.ExportSiteConfig()
+}
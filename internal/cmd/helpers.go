@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+)
+
+var flagAll bool
+var flagBackup bool
+var flagForce bool
+
+// confirm Asks a yes or no question, returning true only if the answer is yes. Input that ends before an answer, such
+// as when stdin isn't a terminal, counts as no.
+func confirm(question string) bool {
+
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		fmt.Println()
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// ensureSiteRunning Exits with an error if the site isn't running or is paused, as commands that run in its containers
+// would otherwise hang until it is resumed
+func ensureSiteRunning(kanaSite *site.Site, command string) {
+
+	if !kanaSite.IsSiteRunning() {
+		console.Error(fmt.Errorf("the %s command only works on a running site. Please run 'kana start' to start the site", command))
+		os.Exit(1)
+	}
+
+	if kanaSite.IsSitePaused() {
+		console.Error(fmt.Errorf("the %s command doesn't work while the site is paused. Please run 'kana resume' to resume it", command))
+		os.Exit(1)
+	}
+}
+
+// errSiteSkipped Is returned by an operation that doesn't apply to a site so it isn't reported as a failure
+type errSiteSkipped string
+
+func (e errSiteSkipped) Error() string {
+	return string(e)
+}
+
+// runOnAllSites Runs an operation on every site, reporting the result for each without stopping on the first error
+func runOnAllSites(kanaSite *site.Site, operation func(*site.Site) error) {
+
+	siteNames, err := site.GetSiteNames(kanaSite.StaticConfig, kanaSite.DynamicConfig)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if len(siteNames) == 0 {
+		console.Info("No sites found.")
+		return
+	}
+
+	failed := 0
+
+	for _, siteName := range siteNames {
+
+		currentSite, err := site.NewSite(kanaSite.StaticConfig, kanaSite.DynamicConfig)
+		if err == nil {
+			err = currentSite.LoadSite(siteName)
+		}
+
+		if err == nil {
+			err = operation(currentSite)
+		}
+
+		var skipped errSiteSkipped
+
+		switch {
+		case errors.As(err, &skipped):
+			console.Info("%s: skipped (%s)", siteName, skipped)
+		case err != nil:
+			failed++
+			console.Error(fmt.Errorf("%s: failed (%s)", siteName, err))
+		default:
+			console.Info("%s: done", siteName)
+		}
+	}
+
+	if failed > 0 {
+		console.Error(fmt.Errorf("%d of %d sites failed", failed, len(siteNames)))
+		os.Exit(1)
+	}
+}
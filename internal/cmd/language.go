@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newLanguageCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "language",
+		Short: "Manage the language packs of the current site.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newLanguageInstallCommand(site),
+		newLanguageListCommand(site),
+	)
+
+	return cmd
+}
+
+func newLanguageInstallCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "install <locale>",
+		Short: "Install a WordPress language pack and save it to the site config.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runLanguageInstall(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().BoolVar(&flagActivate, "activate", false, "Switch the site to the language after installing it.")
+
+	return cmd
+}
+
+func newLanguageListCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the languages available for the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runLanguageList(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func runLanguageInstall(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkLanguageSiteRunning(site)
+
+	err := site.InstallLanguage(args[0], flagActivate)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Installed the %s language pack", args[0])
+}
+
+func runLanguageList(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkLanguageSiteRunning(site)
+
+	output, err := site.GetLanguages()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	fmt.Println(output)
+}
+
+func checkLanguageSiteRunning(site *site.Site) {
+
+	ensureSiteRunning(site, "language")
+}
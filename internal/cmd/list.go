@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/aquasecurity/table"
+	"github.com/spf13/cobra"
+)
+
+var flagOrphans bool
+var flagPrune bool
+
+func newListCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all sites along with the folder each is linked to, whether it is running and its URL.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runList(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagOrphans, "orphans", false, "List site directories that have nothing in them, containers of sites without a directory and sites whose folder has moved.")
+	cmd.Flags().BoolVar(&flagPrune, "prune", false, "Remove the orphaned directories and containers found with --orphans after asking for confirmation.")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Prune without asking for confirmation.")
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Print the list as JSON.")
+
+	return cmd
+}
+
+func runList(cmd *cobra.Command, args []string, kanaSite *site.Site) {
+
+	if flagPrune && !flagOrphans {
+		console.Error(fmt.Errorf("the --prune flag only works with --orphans"))
+		os.Exit(1)
+	}
+
+	if flagOrphans {
+		listOrphans(kanaSite)
+		return
+	}
+
+	sites, err := site.GetSites(kanaSite.StaticConfig, kanaSite.DynamicConfig)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagJSON {
+		printJSON(sites)
+		return
+	}
+
+	if len(sites) == 0 {
+		console.Info("No sites found.")
+		return
+	}
+
+	t := table.New(os.Stdout)
+
+	t.SetHeaders("Name", "Folder", "Status", "URL")
+
+	for _, siteInfo := range sites {
+		t.AddRow(siteInfo.Name, siteInfo.Folder, siteInfo.Status, siteInfo.URL)
+	}
+
+	t.Render()
+}
+
+func listOrphans(kanaSite *site.Site) {
+
+	orphans, err := site.GetOrphans(kanaSite.StaticConfig, kanaSite.DynamicConfig)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagJSON {
+		printJSON(orphans)
+	} else if len(orphans.Directories) == 0 && len(orphans.Containers) == 0 && len(orphans.Moved) == 0 {
+		console.Info("No orphaned sites found.")
+	} else {
+		printOrphans(orphans)
+	}
+
+	if !flagPrune || (len(orphans.Directories) == 0 && len(orphans.Containers) == 0) {
+		return
+	}
+
+	if !flagForce && !confirm(fmt.Sprintf("Remove %s?", strings.Join(getOrphanNames(orphans), ", "))) {
+		console.Info("Nothing was removed.")
+		return
+	}
+
+	err = site.PruneOrphans(orphans)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Pruned %s", strings.Join(getOrphanNames(orphans), ", "))
+}
+
+// printOrphans Prints a table of the orphaned directories and containers along with the sites whose folder has moved
+func printOrphans(orphans site.Orphans) {
+
+	t := table.New(os.Stdout)
+
+	t.SetHeaders("Site", "Orphaned", "Details")
+
+	for _, directory := range orphans.Directories {
+		t.AddRow(directory.Name, "directory", fmt.Sprintf("%s: %s", directory.Directory, directory.Reason))
+	}
+
+	for _, containers := range orphans.Containers {
+		t.AddRow(containers.Name, "containers", fmt.Sprintf("%d container(s) with no site directory", len(containers.Containers)))
+	}
+
+	for _, moved := range orphans.Moved {
+		t.AddRow(moved.Name, "moved", fmt.Sprintf("%s no longer exists. Run 'kana relink' in the folder's new location to keep the site", moved.Link))
+	}
+
+	t.Render()
+}
+
+// getOrphanNames Returns the names of the sites with orphaned directories or containers
+func getOrphanNames(orphans site.Orphans) []string {
+
+	names := []string{}
+
+	for _, directory := range orphans.Directories {
+		names = append(names, directory.Name)
+	}
+
+	for _, containers := range orphans.Containers {
+		names = append(names, containers.Name)
+	}
+
+	return names
+}
+
+// printJSON Prints the value as indented JSON, exiting if it can't be encoded
+func printJSON(value interface{}) {
+
+	output, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	fmt.Println(string(output))
+}
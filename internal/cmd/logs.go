@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagContainer string
+var flagFollow bool
+
+// prefixWriter Writes each complete line with a prefix so the output of several containers can share one writer
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	mutex  *sync.Mutex
+	buffer []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+
+	w.buffer = append(w.buffer, p...)
+
+	for {
+		lineEnd := bytes.IndexByte(w.buffer, '\n')
+		if lineEnd < 0 {
+			break
+		}
+
+		err := w.writeLine(w.buffer[:lineEnd+1])
+		if err != nil {
+			return 0, err
+		}
+
+		w.buffer = w.buffer[lineEnd+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush Writes any partial line left in the buffer
+func (w *prefixWriter) Flush() error {
+
+	if len(w.buffer) == 0 {
+		return nil
+	}
+
+	err := w.writeLine(append(w.buffer, '\n'))
+	w.buffer = nil
+
+	return err
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err := fmt.Fprintf(w.out, "%s | %s\n", w.prefix, bytes.TrimRight(line, "\r\n"))
+
+	return err
+}
+
+func newLogsCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show the logs of the current site's containers.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runLogs(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVarP(&flagContainer, "container", "c", "all", "The container to show logs for, such as \"wordpress\" or \"database\", or \"all\" for every container.")
+	cmd.Flags().BoolVarP(&flagFollow, "follow", "f", false, "Keep showing new log output until stopped with Ctrl+C.")
+
+	return cmd
+}
+
+func runLogs(cmd *cobra.Command, args []string, site *site.Site) {
+
+	if !site.IsSiteRunning() {
+		console.Error(fmt.Errorf("the logs command only works on a running site. Please run 'kana start' to start the site"))
+		os.Exit(1)
+	}
+
+	containers := site.GetSiteContainerNames()
+
+	if flagContainer != "all" {
+		if !appConfig.CheckString(flagContainer, containers) {
+			console.Error(fmt.Errorf("invalid container %q. Please choose one of %v or all", flagContainer, containers))
+			os.Exit(1)
+		}
+
+		containers = []string{flagContainer}
+	}
+
+	prefixWidth := 0
+
+	for _, container := range containers {
+		if len(container) > prefixWidth {
+			prefixWidth = len(container)
+		}
+	}
+
+	mutex := &sync.Mutex{}
+	errors := make(chan error, len(containers))
+
+	var waitGroup sync.WaitGroup
+
+	for _, container := range containers {
+
+		waitGroup.Add(1)
+
+		go func(container string) {
+			defer waitGroup.Done()
+
+			writer := &prefixWriter{
+				prefix: fmt.Sprintf("%-*s", prefixWidth, container),
+				out:    os.Stdout,
+				mutex:  mutex,
+			}
+
+			err := site.FollowLogs(container, flagFollow, writer)
+			if err == nil {
+				err = writer.Flush()
+			}
+
+			if err != nil {
+				errors <- fmt.Errorf("%s: %s", container, err)
+			}
+		}(container)
+	}
+
+	waitGroup.Wait()
+	close(errors)
+
+	failed := false
+
+	for err := range errors {
+		console.Error(err)
+		failed = true
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
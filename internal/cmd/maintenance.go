@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newMaintenanceCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Manage WordPress maintenance mode for the current site.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newMaintenanceToggleCommand(site, true),
+		newMaintenanceToggleCommand(site, false),
+		newMaintenanceStatusCommand(site),
+	)
+
+	return cmd
+}
+
+func newMaintenanceToggleCommand(site *site.Site, enable bool) *cobra.Command {
+
+	use := "off"
+	short := "Turn off maintenance mode so the site is served normally."
+
+	if enable {
+		use = "on"
+		short = "Turn on maintenance mode so visitors see the maintenance page."
+	}
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Run: func(cmd *cobra.Command, args []string) {
+			runMaintenanceToggle(cmd, args, site, enable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func newMaintenanceStatusCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether maintenance mode is on for the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runMaintenanceStatus(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func runMaintenanceToggle(cmd *cobra.Command, args []string, site *site.Site, enable bool) {
+
+	checkMaintenanceSiteRunning(site)
+
+	err := site.SetMaintenanceMode(enable)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if enable {
+		console.Info("Maintenance mode is on")
+		return
+	}
+
+	console.Info("Maintenance mode is off")
+}
+
+func runMaintenanceStatus(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkMaintenanceSiteRunning(site)
+
+	isActive, err := site.IsMaintenanceModeActive()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if isActive {
+		fmt.Println("on")
+		return
+	}
+
+	fmt.Println("off")
+}
+
+func checkMaintenanceSiteRunning(site *site.Site) {
+
+	ensureSiteRunning(site, "maintenance")
+}
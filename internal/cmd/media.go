@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagOnlyMissing bool
+
+func newMediaCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "media",
+		Short: "Export and import the uploads of the current site and regenerate its thumbnails.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newMediaExportCommand(site),
+		newMediaImportCommand(site),
+		newMediaRegenerateCommand(site),
+	)
+
+	return cmd
+}
+
+func newMediaExportCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export the wp-content/uploads directory of the current site to a .tar.gz file.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runMediaExport(cmd, args, site)
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	return cmd
+}
+
+func newMediaImportCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import a .tar.gz file created with 'kana media export' into the wp-content/uploads directory of the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runMediaImport(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func newMediaRegenerateCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "regenerate",
+		Short: "Regenerate the thumbnails of the current site's images, such as after importing a database with different image sizes.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runMediaRegenerate(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagOnlyMissing, "only-missing", false, "Only generate the sizes each image is missing instead of replacing all of them.")
+
+	return cmd
+}
+
+func runMediaExport(cmd *cobra.Command, args []string, site *site.Site) {
+
+	exportFile := fmt.Sprintf("%s-uploads.tar.gz", site.StaticConfig.SiteName)
+
+	if len(args) == 1 {
+		exportFile = args[0]
+	}
+
+	err := site.ExportMedia(exportFile)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Uploads exported to %s", exportFile)
+}
+
+func runMediaImport(cmd *cobra.Command, args []string, site *site.Site) {
+
+	err := site.ImportMedia(args[0])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Uploads imported from %s", args[0])
+}
+
+func runMediaRegenerate(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "media regenerate")
+
+	summary, err := site.RegenerateMedia(flagOnlyMissing)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if len(summary) == 0 {
+		summary = "Regenerated the thumbnails"
+	}
+
+	console.Info("%s", summary)
+}
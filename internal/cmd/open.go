@@ -3,32 +3,45 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 	"github.com/ChrisWiegman/kana-cli/internal/site"
 
 	"github.com/spf13/cobra"
 )
 
-func newOpenCommand(site *site.Site) *cobra.Command {
+var flagService string
+
+func newOpenCommand(kanaSite *site.Site) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "open",
 		Short: "Open the current site in your browser.",
 		Run: func(cmd *cobra.Command, args []string) {
-			runOpen(cmd, args, site)
+			runOpen(cmd, args, kanaSite)
 		},
 		Args: cobra.NoArgs,
 	}
 
+	cmd.Flags().StringVar(&flagService, "service", "", fmt.Sprintf("Open a companion's web UI instead of the site (%s)", strings.Join(site.GetCompanionNames(), ", ")))
+
 	return cmd
 }
 
-func runOpen(cmd *cobra.Command, args []string, site *site.Site) {
+func runOpen(cmd *cobra.Command, args []string, kanaSite *site.Site) {
+
+	var err error
+
+	if len(flagService) > 0 {
+		err = kanaSite.OpenCompanion(flagService)
+	} else {
+		// Open the site in the user's default browser,
+		err = kanaSite.OpenSite()
+	}
 
-	// Open the site in the user's default browser,
-	err := site.OpenSite()
 	if err != nil {
-		fmt.Println(err)
+		console.Error(err)
 		os.Exit(1)
 	}
 }
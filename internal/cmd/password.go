@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagUser string
+
+func newResetAdminPasswordCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "reset-admin-password [password]",
+		Short: "Reset the password of the site's admin user, generating a secure one if none is given.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runResetAdminPassword(cmd, args, site)
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&flagUser, "user", "u", "", "The user to reset the password of. Defaults to the admin.username setting.")
+
+	return cmd
+}
+
+func runResetAdminPassword(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "reset-admin-password")
+
+	user := flagUser
+	if len(user) == 0 {
+		user = site.DynamicConfig.GetString("admin.username")
+	}
+
+	password := ""
+	if len(args) == 1 {
+		password = args[0]
+	}
+
+	password, err := site.ResetPassword(user, password)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("The password of %s has been reset", user)
+
+	// Print the password itself as command output so it's still shown with --quiet
+	fmt.Println(password)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newPauseCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pauses the containers of the current site so they stop using CPU, keeping their state until 'kana resume'.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runPause(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func newResumeCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resumes the containers of the current site paused with 'kana pause'.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runResume(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func runPause(cmd *cobra.Command, args []string, site *site.Site) {
+
+	if !site.IsSiteRunning() {
+		console.Error(fmt.Errorf("the pause command only works on a running site. Please run 'kana start' to start the site"))
+		os.Exit(1)
+	}
+
+	if site.IsSitePaused() {
+		console.Info("The site is already paused")
+		return
+	}
+
+	err := site.PauseSite()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Paused the site's containers. Run 'kana resume' to resume them")
+}
+
+func runResume(cmd *cobra.Command, args []string, site *site.Site) {
+
+	if !site.IsSiteRunning() {
+		console.Error(fmt.Errorf("the resume command only works on a running site. Please run 'kana start' to start the site"))
+		os.Exit(1)
+	}
+
+	err := site.ResumeSite()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Resumed the site's containers")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newPluginCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage the plugins of the current site.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newPluginInstallCommand(site),
+		newPluginScaffoldCommand(site),
+	)
+
+	return cmd
+}
+
+func newPluginInstallCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "install [slug]",
+		Short: "Install and activate a plugin from WordPress.org and save it to the site config, or the plugins in the site config if no slug is given.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runPluginInstall(cmd, args, site)
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	return cmd
+}
+
+func newPluginScaffoldCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "scaffold <slug> [wp-cli flags]",
+		Short: "Generate a new plugin in the current folder with wp scaffold plugin.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runScaffold(cmd, args, site, site.ScaffoldPlugin)
+		},
+		Args: cobra.MinimumNArgs(1),
+	}
+
+	// Pass every flag through to wp-cli
+	cmd.DisableFlagParsing = true
+
+	return cmd
+}
+
+func runPluginInstall(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "plugin")
+
+	// Sites started with --skip-plugins can install their default plugins later
+	if len(args) == 0 {
+
+		err := site.InstallDefaultPlugins()
+		if err != nil {
+			console.Error(err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	err := site.InstallPlugin(args[0])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Installed the %s plugin", args[0])
+}
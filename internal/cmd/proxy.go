@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+	"github.com/ChrisWiegman/kana-cli/internal/traefik"
+
+	"github.com/spf13/cobra"
+)
+
+func newProxyCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Manage the Traefik proxy shared by all sites.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newProxyRestartCommand(site),
+		newProxyLogsCommand(site),
+	)
+
+	return cmd
+}
+
+func newProxyRestartCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Recreate the Traefik proxy to fix broken routing or apply config changes.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runProxyRestart(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func newProxyLogsCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show the logs of the Traefik proxy, including access logs if traefik.accessLog is enabled.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runProxyLogs(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVarP(&flagFollow, "follow", "f", false, "Keep showing new log output until stopped with Ctrl+C.")
+
+	return cmd
+}
+
+func runProxyRestart(cmd *cobra.Command, args []string, site *site.Site) {
+
+	traefikClient, err := traefik.NewTraefik(site.StaticConfig, site.DynamicConfig)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	err = traefikClient.RestartTraefik()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("The proxy has been restarted")
+}
+
+func runProxyLogs(cmd *cobra.Command, args []string, site *site.Site) {
+
+	traefikClient, err := traefik.NewTraefik(site.StaticConfig, site.DynamicConfig)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	err = traefikClient.FollowLogs(flagFollow, os.Stdout)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newPruneCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Removes wp-cli images no site uses anymore along with orphaned site directories and containers, after asking for confirmation.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runPrune(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Remove orphaned site directories and containers without asking for confirmation.")
+
+	return cmd
+}
+
+func runPrune(cmd *cobra.Command, args []string, kanaSite *site.Site) {
+
+	orphans, err := site.GetOrphans(kanaSite.StaticConfig, kanaSite.DynamicConfig)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if len(orphans.Directories) > 0 || len(orphans.Containers) > 0 || len(orphans.Moved) > 0 {
+		printOrphans(orphans)
+	}
+
+	if len(orphans.Directories) > 0 || len(orphans.Containers) > 0 {
+
+		if flagForce || confirm(fmt.Sprintf("Remove %s?", strings.Join(getOrphanNames(orphans), ", "))) {
+
+			err = site.PruneOrphans(orphans)
+			if err != nil {
+				console.Error(err)
+				os.Exit(1)
+			}
+
+			console.Info("Pruned %s", strings.Join(getOrphanNames(orphans), ", "))
+		} else {
+			console.Info("Kept the orphaned site directories and containers.")
+		}
+	}
+
+	removedImages, err := site.PruneCLIImages(kanaSite.StaticConfig, kanaSite.DynamicConfig)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if len(removedImages) > 0 {
+		console.Info("Removed unused images %s", strings.Join(removedImages, ", "))
+	}
+
+	if len(orphans.Directories) == 0 && len(orphans.Containers) == 0 && len(removedImages) == 0 {
+		console.Info("Nothing to prune.")
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newRelinkCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "relink [folder]",
+		Short: "Links the current site to the given folder, or the current folder, such as after moving the project.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runRelink(cmd, args, site)
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	return cmd
+}
+
+func runRelink(cmd *cobra.Command, args []string, site *site.Site) {
+
+	folder, err := os.Getwd()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if len(args) == 1 {
+		folder = args[0]
+	}
+
+	err = site.RelinkSite(folder)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Linked site %s to %s", site.StaticConfig.SiteName, site.StaticConfig.WorkingDirectory)
+
+	// The containers still mount the old folder until they are recreated
+	if site.IsSiteRunning() {
+		console.Warn("Run 'kana stop' and 'kana start' so the site uses the files in %s", site.StaticConfig.WorkingDirectory)
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newRenameCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "rename <new name>",
+		Short: "Renames the current site, keeping its database and files.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runRename(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func runRename(cmd *cobra.Command, args []string, site *site.Site) {
+
+	oldName := site.StaticConfig.SiteName
+
+	err := site.RenameSite(args[0])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Renamed site %s to %s: %s", oldName, site.StaticConfig.SiteName, site.GetURL(false))
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newRestartCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Restarts the containers of the current site without recreating them.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runRestart(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVarP(&flagContainer, "container", "c", "all", "The container to restart, such as \"wordpress\" or \"database\", or \"all\" for every container.")
+
+	return cmd
+}
+
+func runRestart(cmd *cobra.Command, args []string, site *site.Site) {
+
+	if !site.IsSiteRunning() {
+		console.Error(fmt.Errorf("the restart command only works on a running site. Please run 'kana start' to start the site"))
+		os.Exit(1)
+	}
+
+	var err error
+
+	if flagContainer == "all" {
+		err = site.RestartWordPress()
+	} else {
+		err = site.RestartContainer(flagContainer)
+	}
+
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if flagContainer == "all" {
+		console.Info("Restarted the site's containers")
+	} else {
+		console.Info("Restarted the %s container", flagContainer)
+	}
+}
@@ -1,45 +1,52 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
 	"github.com/ChrisWiegman/kana-cli/internal/appSetup"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/docker"
 	"github.com/ChrisWiegman/kana-cli/internal/site"
 
 	"github.com/spf13/cobra"
 )
 
 var flagName string
+var flagQuiet bool
+var flagVerbose bool
+var flagLogFormat string
 
 func Execute() {
 
 	// Setup the static config items that cannot be overripen
 	staticConfig, err := appConfig.GetStaticConfig()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		console.Error(err)
 		os.Exit(1)
 	}
 
-	// Ensure the static content files are in place and up to date
-	err = appSetup.EnsureStaticConfigFiles(staticConfig)
+	// Get the dynamic config that the user might have set themselves
+	dynamicConfig, err := appConfig.GetDynamicContent(staticConfig)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		console.Error(err)
 		os.Exit(1)
 	}
 
-	// Get the dynamic config that the user might have set themselves
-	dynamicConfig, err := appConfig.GetDynamicContent(staticConfig)
+	// Ensure the static content files are in place and up to date
+	err = appSetup.EnsureStaticConfigFiles(staticConfig, dynamicConfig)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		console.Error(err)
 		os.Exit(1)
 	}
 
+	// Connect to the Docker host from the config instead of detecting it if one is set
+	docker.SetHost(dynamicConfig.GetString("docker.host"))
+
 	// Create a site object
 	site, err := site.NewSite(staticConfig, dynamicConfig)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		console.Error(err)
 		os.Exit(1)
 	}
 
@@ -49,32 +56,79 @@ func Execute() {
 		Short: "Kana is a simple WordPress development tool designed for plugin and theme developers.",
 		Args:  cobra.NoArgs,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			err := site.ProcessNameFlag(cmd)
+			if flagQuiet {
+				console.SetLevel(console.LevelWarn)
+			} else if flagVerbose {
+				console.SetLevel(console.LevelDebug)
+			}
+
+			logFormat, err := console.ParseFormat(flagLogFormat)
+			if err != nil {
+				console.Error(err)
+				os.Exit(1)
+			}
+
+			console.SetFormat(logFormat)
+
+			err = site.ProcessNameFlag(cmd)
 			if err != nil {
-				fmt.Fprintln(os.Stderr, err)
+				console.Error(err)
 				os.Exit(1)
 			}
+
+			console.SetSite(site.StaticConfig.SiteName)
+
+			// The Docker client is created before the flags are parsed so the host it found is reported here
+			console.Debug("Using the Docker host at %s", docker.GetHost())
 		},
 	}
 
 	// Add the "name" flag to allow for sites not connected to the local directory
 	cmd.PersistentFlags().StringVarP(&flagName, "name", "n", "", "Specify a name for the site, used to override using the current folder.")
+	cmd.PersistentFlags().BoolVarP(&flagQuiet, "quiet", "q", false, "Only print warnings, errors and the output of commands.")
+	cmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "Also print debugging messages, such as which Docker host is used.")
+	cmd.PersistentFlags().StringVar(&flagLogFormat, "log-format", "text", "Format of log messages, text or json. JSON prints one object per line for use in CI.")
 
 	// Register the subcommands
 	cmd.AddCommand(
 		newStartCommand(site),
 		newStopCommand(site),
+		newRestartCommand(site),
+		newPauseCommand(site),
+		newResumeCommand(site),
+		newListCommand(site),
+		newPruneCommand(site),
 		newOpenCommand(site),
 		newWPCommand(site),
+		newVerifyCommand(site),
+		newLogsCommand(site),
 		newDestroyCommand(site),
+		newRenameCommand(site),
+		newRelinkCommand(site),
+		newDBCommand(site),
+		newMediaCommand(site),
+		newMaintenanceCommand(site),
+		newXdebugCommand(site),
+		newCacheCommand(site),
+		newResetAdminPasswordCommand(site),
+		newAuthCommand(site),
+		newThemeCommand(site),
+		newPluginCommand(site),
+		newScaffoldCommand(site),
+		newLanguageCommand(site),
+		newBackupCommand(site),
+		newWatchCommand(site),
 		newConfigCommand(site),
+		newProxyCommand(site),
 		newExportCommand(site),
+		newUpdateCommand(site),
+		newEnvCommand(site),
 		newVersionCommand(site),
 	)
 
 	// Execute anything we need to
 	if err := cmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		console.Error(err)
 		os.Exit(1)
 	}
 }
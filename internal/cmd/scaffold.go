@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newScaffoldCommand(kanaSite *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("scaffold <%s> <slug> [wp-cli flags]", strings.Join(site.ScaffoldComponents, "|")),
+		Short: "Add a block, post type or taxonomy to the plugin or theme of the current site with wp scaffold.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runScaffold(cmd, args[1:], kanaSite, func(componentArgs []string) (string, error) {
+				return kanaSite.ScaffoldComponent(args[0], componentArgs)
+			})
+		},
+		Args: cobra.MinimumNArgs(2),
+	}
+
+	// Pass every flag through to wp-cli
+	cmd.DisableFlagParsing = true
+
+	return cmd
+}
+
+// runScaffold Runs one of the site's scaffold commands and prints its output
+func runScaffold(cmd *cobra.Command, args []string, site *site.Site, scaffold func(args []string) (string, error)) {
+
+	ensureSiteRunning(site, "scaffold")
+
+	output, err := scaffold(args)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	fmt.Println(output)
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"time"
 
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 	"github.com/ChrisWiegman/kana-cli/internal/site"
 	"github.com/ChrisWiegman/kana-cli/internal/traefik"
 
@@ -14,6 +18,13 @@ var flagXdebug bool
 var flagLocal bool
 var flagIsTheme bool
 var flagIsPlugin bool
+var flagKeepConfig bool
+var flagInsecure bool
+var flagGitignore bool
+var flagSkipPlugins bool
+var flagWait bool
+var flagWaitTimeout time.Duration
+var flagFresh bool
 
 func newStartCommand(site *site.Site) *cobra.Command {
 
@@ -31,89 +42,213 @@ func newStartCommand(site *site.Site) *cobra.Command {
 	cmd.Flags().BoolVarP(&flagIsPlugin, "plugin", "p", false, "Run the site as a plugin using the current folder as the plugin source.")
 	cmd.Flags().BoolVarP(&flagIsTheme, "theme", "t", false, "Run the site as a theme using the current folder as the theme source.")
 	cmd.Flags().BoolVarP(&flagLocal, "local", "l", false, "Installs the WordPress files in your current path at ./wordpress instead of the global app path.")
+	cmd.Flags().BoolVar(&flagInsecure, "insecure", false, "Serve the site over plain http without TLS.")
+	cmd.Flags().BoolVar(&flagKeepConfig, "keep-config", false, "Keep an existing wp-config.php file in local sites, only updating its database settings.")
+	cmd.Flags().BoolVar(&flagGitignore, "gitignore", true, "Add the local WordPress files to the .gitignore file in your current path.")
+	cmd.Flags().BoolVar(&flagSkipPlugins, "skip-plugins", false, "Don't install the plugins listed in the site config for a faster start.")
+	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, "Start all existing sites using their saved config.")
+	cmd.Flags().BoolVar(&flagWait, "wait", false, "Wait until the site, and its REST API if verified, responds correctly before returning, exiting with an error if it doesn't.")
+	cmd.Flags().DurationVar(&flagWaitTimeout, "wait-timeout", 2*time.Minute, "How long --wait waits for the site to be ready.")
+	cmd.Flags().BoolVar(&flagFresh, "fresh", false, "Remove the site's containers, even if they are running, and create them again from the current config. The site's database and files are kept.")
 
 	return cmd
 }
 
 func runStart(cmd *cobra.Command, args []string, kanaSite *site.Site) {
 
+	if flagAll {
+		runOnAllSites(kanaSite, func(currentSite *site.Site) error {
+			if currentSite.IsSiteRunning() && !flagFresh {
+				return errSiteSkipped("already running")
+			}
+
+			return startSite(currentSite)
+		})
+
+		return
+	}
+
 	// A site shouldn't be both a plugin and a theme so this reports an error if that is the case.
 	if flagIsPlugin && flagIsTheme {
-		fmt.Println(fmt.Errorf("you have set both the plugin and theme flags. Please choose only one option"))
+		console.Error(fmt.Errorf("you have set both the plugin and theme flags. Please choose only one option"))
 		os.Exit(1)
 	}
 
-	// Check that the site is already running and show an error if it is.
-	if kanaSite.IsSiteRunning() {
-		fmt.Println("Site is already running. Please stop your site before running the start command")
+	// Check that the site is already running and show an error if it is. Fresh starts replace the running containers.
+	if kanaSite.IsSiteRunning() && !flagFresh {
+		if kanaSite.IsSitePaused() {
+			console.Error(fmt.Errorf("site is paused. Please run 'kana resume' to resume it"))
+			os.Exit(1)
+		}
+
+		console.Error(fmt.Errorf("site is already running. Please stop your site or use the --fresh flag to recreate its containers"))
 		os.Exit(1)
 	}
 
 	// Process any overrides set with flags on the start command
 	startFlags := site.SiteFlags{
-		Xdebug:   flagXdebug,
-		IsTheme:  flagIsTheme,
-		IsPlugin: flagIsPlugin,
-		Local:    flagLocal,
+		Xdebug:      flagXdebug,
+		IsTheme:     flagIsTheme,
+		IsPlugin:    flagIsPlugin,
+		Local:       flagLocal,
+		KeepConfig:  flagKeepConfig,
+		Insecure:    flagInsecure,
+		Gitignore:   flagGitignore,
+		SkipPlugins: flagSkipPlugins,
 	}
 
 	kanaSite.ProcessSiteFlags(cmd, startFlags)
 
+	err := startSite(kanaSite)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	// Open the site in the user's browser
+	err = kanaSite.OpenSite()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+}
+
+// startSite Starts the site and all the services it depends on, installing WordPress as needed
+func startSite(kanaSite *site.Site) error {
+
+	// Catch hand edited config mistakes before they fail somewhere in the middle of starting the site
+	err := validateConfig(kanaSite)
+	if err != nil {
+		return err
+	}
+
+	if flagFresh {
+		console.Info("Removing the site's containers so they are created again from the current config")
+
+		err = kanaSite.RemoveContainers()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Let's start everything up
-	fmt.Printf("Starting development site: %s\n", kanaSite.GetURL(false))
+	console.Info("Starting development site: %s", kanaSite.GetURL(false))
 
 	// Start Traefik if we need it
-	traefikClient, err := traefik.NewTraefik(kanaSite.StaticConfig)
+	traefikClient, err := traefik.NewTraefik(kanaSite.StaticConfig, kanaSite.DynamicConfig)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	err = traefikClient.StartTraefik()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	// Start WordPress
 	err = kanaSite.StartWordPress()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	// Make sure the WordPress site is running
+	spinner := console.StartSpinner("Waiting for the site to respond...")
+
 	_, err = kanaSite.VerifySite()
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+
+	spinner.Stop()
+
+	if errors.Is(err, site.ErrMaintenanceMode) {
+		console.Warn("The site is in maintenance mode. Run 'kana maintenance off' to turn it off")
+	} else if err != nil {
+		return err
 	}
 
 	// Setup WordPress
-	err = kanaSite.InstallWordPress()
+	installResult, err := kanaSite.InstallWordPress()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
-	// Install Xdebug if we need to
-	_, err = kanaSite.InstallXdebug()
+	// Create any additional users
+	err = kanaSite.InstallUsers()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
+	}
+
+	// Install Xdebug and any other PHP extensions the site needs
+	_, err = kanaSite.InstallPHPExtensions()
+	if err != nil {
+		return err
 	}
 
 	// Install any configuration plugins if needed
-	err = kanaSite.InstallDefaultPlugins()
+	if kanaSite.Settings.SkipPlugins {
+		console.Info("Skipping the plugins in the site config")
+	} else {
+		err = kanaSite.InstallDefaultPlugins()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Install and activate any configured themes
+	err = kanaSite.InstallDefaultThemes()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
-	// Open the site in the user's browser
-	err = kanaSite.OpenSite()
+	// Install and switch to any configured languages
+	err = kanaSite.InstallDefaultLanguages()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
+	}
+
+	// Give new sites sample posts, pages and menus to work with
+	if installResult.Installed && kanaSite.Settings.DemoContent {
+		err = kanaSite.ImportDemoContent()
+		if err != nil {
+			return err
+		}
+	}
+
+	if installResult.Installed {
+		console.Info("Installed WordPress %s", installResult.Version)
+	} else {
+		console.Info("Using the existing WordPress %s install", installResult.Version)
+	}
+
+	console.Info("Your site is ready at %s", installResult.URL)
+	console.Info("The dashboard is at %s", installResult.AdminURL)
+
+	phpVersionURLs := kanaSite.GetPHPVersionURLs()
+	phpVersions := []string{}
+
+	for phpVersion := range phpVersionURLs {
+		phpVersions = append(phpVersions, phpVersion)
+	}
+
+	sort.Strings(phpVersions)
+
+	for _, phpVersion := range phpVersions {
+		console.Info("PHP %s is running at %s", phpVersion, phpVersionURLs[phpVersion])
 	}
+	console.Info("Login with username %s and password %s", installResult.Username, installResult.Password)
+
+	if connection, isExposed := kanaSite.GetDatabaseConnection(); isExposed {
+		console.Info("Connect to the database at %s", connection)
+	}
+
+	if flagWait {
+		console.Info("Waiting for the site to be ready...")
+
+		err = kanaSite.WaitForSite(flagWaitTimeout)
+		if err != nil {
+			return err
+		}
+
+		console.Info("The site is ready")
+	}
+
+	return nil
 }
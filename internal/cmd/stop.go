@@ -1,9 +1,9 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 	"github.com/ChrisWiegman/kana-cli/internal/site"
 
 	"github.com/spf13/cobra"
@@ -20,15 +20,47 @@ func newStopCommand(site *site.Site) *cobra.Command {
 		Args: cobra.NoArgs,
 	}
 
+	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, "Stop all sites.")
+	cmd.Flags().BoolVar(&flagBackup, "backup", false, "Back up the database of running sites before stopping them.")
+
 	return cmd
 }
 
 func runStop(cmd *cobra.Command, args []string, site *site.Site) {
 
+	if flagAll {
+		runOnAllSites(site, stopSite)
+		return
+	}
+
+	if flagBackup && site.IsSiteRunning() {
+		err := backupSite(site)
+		if err != nil {
+			console.Error(err)
+			os.Exit(1)
+		}
+	}
+
 	// Stop the WordPress site
 	err := site.StopWordPress()
 	if err != nil {
-		fmt.Println(err)
+		console.Error(err)
 		os.Exit(1)
 	}
 }
+
+func stopSite(kanaSite *site.Site) error {
+
+	if !kanaSite.IsSiteRunning() {
+		return errSiteSkipped("not running")
+	}
+
+	if flagBackup {
+		err := backupSite(kanaSite)
+		if err != nil {
+			return err
+		}
+	}
+
+	return kanaSite.StopWordPress()
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/aquasecurity/table"
+	"github.com/spf13/cobra"
+)
+
+var flagActivate bool
+
+func newThemeCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "theme",
+		Short: "Manage the themes of the current site.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newThemeInstallCommand(site),
+		newThemeActivateCommand(site),
+		newThemeRemoveCommand(site),
+		newThemeListCommand(site),
+		newThemeScaffoldCommand(site),
+	)
+
+	return cmd
+}
+
+func newThemeInstallCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "install <slug>",
+		Short: "Install a theme from WordPress.org and save it to the site config.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runThemeInstall(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().BoolVar(&flagActivate, "activate", false, "Activate the theme after installing it.")
+
+	return cmd
+}
+
+func newThemeScaffoldCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "scaffold <slug> [wp-cli flags]",
+		Short: "Generate a new theme in the current folder with wp scaffold _s, or wp scaffold child-theme when --parent_theme is set.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runScaffold(cmd, args, site, site.ScaffoldTheme)
+		},
+		Args: cobra.MinimumNArgs(1),
+	}
+
+	// Pass every flag through to wp-cli
+	cmd.DisableFlagParsing = true
+
+	return cmd
+}
+
+func newThemeActivateCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "activate <slug>",
+		Short: "Activate an installed theme and save it to the site config.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runThemeActivate(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func newThemeRemoveCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "remove <slug>",
+		Short: "Delete a theme and remove it from the site config.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runThemeRemove(cmd, args, site)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func newThemeListCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the themes installed on the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runThemeList(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func runThemeInstall(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkThemeSiteRunning(site)
+
+	err := site.InstallTheme(args[0], flagActivate)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Installed the %s theme", args[0])
+}
+
+func runThemeActivate(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkThemeSiteRunning(site)
+
+	err := site.ActivateTheme(args[0])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Activated the %s theme", args[0])
+}
+
+func runThemeRemove(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkThemeSiteRunning(site)
+
+	err := site.RemoveTheme(args[0])
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Removed the %s theme", args[0])
+}
+
+func runThemeList(cmd *cobra.Command, args []string, site *site.Site) {
+
+	checkThemeSiteRunning(site)
+
+	themes, err := site.GetThemes()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	t := table.New(os.Stdout)
+
+	t.SetHeaders("Name", "Status", "Version", "Update")
+
+	for _, theme := range themes {
+		t.AddRow(theme.Name, theme.Status, theme.Version, theme.Update)
+	}
+
+	t.Render()
+}
+
+func checkThemeSiteRunning(site *site.Site) {
+
+	ensureSiteRunning(site, "theme")
+}
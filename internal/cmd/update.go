@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newUpdateCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update WordPress, its plugins and its themes on the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runUpdate(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, "Update all running sites.")
+
+	return cmd
+}
+
+func runUpdate(cmd *cobra.Command, args []string, site *site.Site) {
+
+	if flagAll {
+		runOnAllSites(site, updateSite)
+		return
+	}
+
+	ensureSiteRunning(site, "update")
+
+	err := site.UpdateWordPress()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	console.Info("Updated WordPress, its plugins and its themes")
+}
+
+func updateSite(kanaSite *site.Site) error {
+
+	if !kanaSite.IsSiteRunning() {
+		return errSiteSkipped("not running")
+	}
+
+	if kanaSite.IsSitePaused() {
+		return errSiteSkipped("paused")
+	}
+
+	return kanaSite.UpdateWordPress()
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/aquasecurity/table"
+	"github.com/spf13/cobra"
+)
+
+func newVerifyCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the WordPress core files and plugins of the current site against their checksums from WordPress.org.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runVerify(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Print the modified files as JSON.")
+
+	return cmd
+}
+
+func runVerify(cmd *cobra.Command, args []string, site *site.Site) {
+
+	ensureSiteRunning(site, "verify")
+
+	modifiedFiles, unverifiedPlugins, err := site.VerifyChecksums()
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if len(unverifiedPlugins) > 0 {
+		console.Warn("Unable to verify %s as WordPress.org doesn't have checksums for them", strings.Join(unverifiedPlugins, ", "))
+	}
+
+	if flagJSON {
+		printJSON(modifiedFiles)
+	} else if len(modifiedFiles) > 0 {
+
+		t := table.New(os.Stdout)
+
+		t.SetHeaders("Source", "File", "Problem")
+
+		for _, modifiedFile := range modifiedFiles {
+			t.AddRow(modifiedFile.Source, modifiedFile.File, modifiedFile.Message)
+		}
+
+		t.Render()
+	}
+
+	if len(modifiedFiles) > 0 {
+		console.Error(fmt.Errorf("%d file(s) don't match their checksums", len(modifiedFiles)))
+		os.Exit(1)
+	}
+
+	console.Info("All WordPress core files and plugins match their checksums")
+}
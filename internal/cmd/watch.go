@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+type siteActivity struct {
+	receivedBytes uint64
+	lastActive    time.Time
+}
+
+func newWatchCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Stops sites that haven't received any requests for the idleTimeout setting.",
+		Run: func(cmd *cobra.Command, args []string) {
+			runWatch(cmd, args, site)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func runWatch(cmd *cobra.Command, args []string, kanaSite *site.Site) {
+
+	if kanaSite.DynamicConfig.GetInt("idleTimeout") < 1 {
+		console.Error(fmt.Errorf("the idleTimeout setting must be greater than 0. Please run 'kana config idleTimeout <minutes>' to fix it"))
+		os.Exit(1)
+	}
+
+	idleTimeout := time.Duration(kanaSite.DynamicConfig.GetInt("idleTimeout")) * time.Minute
+
+	console.Info("Stopping sites after %s without requests. Press Ctrl+C to stop watching.", idleTimeout)
+
+	activity := map[string]siteActivity{}
+
+	for {
+		err := stopIdleSites(kanaSite, idleTimeout, activity)
+		if err != nil {
+			console.Error(err)
+			os.Exit(1)
+		}
+
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+// stopIdleSites Stops every running site whose WordPress container hasn't received any traffic for the idle timeout
+func stopIdleSites(kanaSite *site.Site, idleTimeout time.Duration, activity map[string]siteActivity) error {
+
+	siteNames, err := site.GetSiteNames(kanaSite.StaticConfig, kanaSite.DynamicConfig)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, siteName := range siteNames {
+
+		currentSite, err := site.NewSite(kanaSite.StaticConfig, kanaSite.DynamicConfig)
+		if err == nil {
+			err = currentSite.LoadSite(siteName)
+		}
+
+		if err != nil {
+			console.Warn("Unable to load %s: %s", siteName, err)
+			continue
+		}
+
+		if !currentSite.IsSiteRunning() {
+			delete(activity, siteName)
+			continue
+		}
+
+		receivedBytes, err := currentSite.GetReceivedBytes()
+		if err != nil {
+			console.Warn("Unable to check %s for requests: %s", siteName, err)
+			continue
+		}
+
+		lastActivity, found := activity[siteName]
+
+		// Sites seen for the first time, or restarted since, count as active now
+		if !found || receivedBytes != lastActivity.receivedBytes {
+			activity[siteName] = siteActivity{receivedBytes, now}
+			continue
+		}
+
+		if now.Sub(lastActivity.lastActive) < idleTimeout {
+			continue
+		}
+
+		err = currentSite.StopWordPress()
+		if err != nil {
+			console.Warn("Unable to stop %s: %s", siteName, err)
+			continue
+		}
+
+		delete(activity, siteName)
+
+		console.Info("Stopped %s after %s without requests. Run 'kana start' to start it again.", siteName, idleTimeout)
+	}
+
+	return nil
+}
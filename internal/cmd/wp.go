@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 	"github.com/ChrisWiegman/kana-cli/internal/site"
 
 	"github.com/spf13/cobra"
@@ -27,17 +29,79 @@ func newWPCommand(site *site.Site) *cobra.Command {
 
 func runWP(cmd *cobra.Command, args []string, site *site.Site) {
 
-	if !site.IsSiteRunning() {
-		fmt.Println("The wp command only works on a running site. Please run 'kana start' to start the site.")
-		os.Exit(1)
+	// Flag parsing is disabled so wp-cli gets every flag, so pull out Kana's own flags ourselves
+	args, remote := getArg(args, "remote")
+
+	isEvalFile := len(args) > 1 && args[0] == "eval-file"
+
+	if len(remote) > 0 {
+
+		// The file would have to be copied to the remote machine
+		if isEvalFile {
+			console.Error(fmt.Errorf("eval-file can't be used with a remote site. Please pass the code to 'kana wp eval' instead"))
+			os.Exit(1)
+		}
+
+		statusCode, output, err := site.RunRemoteWPCli(remote, args)
+		if err != nil {
+			console.Error(err)
+			os.Exit(1)
+		}
+
+		fmt.Println(output)
+
+		// Scripts need to know when the remote command failed
+		if statusCode != 0 {
+			os.Exit(int(statusCode))
+		}
+
+		return
 	}
 
+	ensureSiteRunning(site, "wp")
+
+	// Check the user exists first as wp-cli runs the command as nobody if it doesn't
+	args, user := getArg(args, "user")
+
+	var output string
+	var err error
+
 	// Run the output from wp-cli
-	output, err := site.RunWPCli(args)
+	if isEvalFile {
+		output, err = site.EvalFile(args[1], args[2:], user, os.Stdin)
+	} else if len(user) > 0 {
+		output, err = site.RunWPCliAsUser(args, user)
+	} else {
+		output, err = site.RunWPCli(args)
+	}
+
 	if err != nil {
-		fmt.Println(err)
+		console.Error(err)
 		os.Exit(1)
 	}
 
 	fmt.Println(output)
 }
+
+// getArg Removes the named flag from the wp-cli arguments, returning the remaining arguments and the value it was set to
+func getArg(args []string, name string) ([]string, string) {
+
+	remainingArgs := []string{}
+	value := ""
+	flag := fmt.Sprintf("--%s", name)
+
+	for i := 0; i < len(args); i++ {
+
+		switch {
+		case strings.HasPrefix(args[i], flag+"="):
+			value = strings.TrimPrefix(args[i], flag+"=")
+		case args[i] == flag && i+1 < len(args):
+			value = args[i+1]
+			i++
+		default:
+			remainingArgs = append(remainingArgs, args[i])
+		}
+	}
+
+	return remainingArgs, value
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func newXdebugCommand(site *site.Site) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "xdebug",
+		Short: "Manage Xdebug for the current site.",
+		Args:  cobra.NoArgs,
+	}
+
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Turn the Xdebug profiler on or off, writing cachegrind files to a folder on your computer.",
+		Args:  cobra.NoArgs,
+	}
+
+	profileCmd.AddCommand(
+		newXdebugProfileToggleCommand(site, true),
+		newXdebugProfileToggleCommand(site, false),
+	)
+
+	cmd.AddCommand(profileCmd)
+
+	return cmd
+}
+
+func newXdebugProfileToggleCommand(site *site.Site, enable bool) *cobra.Command {
+
+	use := "off"
+	short := "Turn off the Xdebug profiler."
+
+	if enable {
+		use = "on"
+		short = "Turn on the Xdebug profiler for requests with the XDEBUG_TRIGGER cookie or parameter."
+	}
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Run: func(cmd *cobra.Command, args []string) {
+			runXdebugProfileToggle(cmd, args, site, enable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func runXdebugProfileToggle(cmd *cobra.Command, args []string, site *site.Site, enable bool) {
+
+	ensureSiteRunning(site, "xdebug")
+
+	outputDirectory, err := site.SetXdebugProfiling(enable)
+	if err != nil {
+		console.Error(err)
+		os.Exit(1)
+	}
+
+	if enable {
+		console.Info("The Xdebug profiler is on. Add XDEBUG_TRIGGER=1 as a cookie or query parameter to a request to profile it and open the files in %s with a tool such as KCachegrind or QCachegrind", outputDirectory)
+		return
+	}
+
+	console.Info("The Xdebug profiler is off")
+}
@@ -0,0 +1,157 @@
+package console
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatJSON
+)
+
+// Fielder Is implemented by errors that carry extra context for structured logs
+type Fielder interface {
+	Fields() map[string]interface{}
+}
+
+type logEntry struct {
+	Level     string                 `json:"level"`
+	Message   string                 `json:"message"`
+	Site      string                 `json:"site,omitempty"`
+	Timestamp string                 `json:"timestamp"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+var currentLevel = LevelInfo
+var currentFormat = FormatText
+var currentSite string
+
+// SetLevel Sets the minimum level of message that will be printed
+func SetLevel(level Level) {
+	currentLevel = level
+}
+
+// IsEnabled Returns true if messages at the given level will be printed
+func IsEnabled(level Level) bool {
+	return level >= currentLevel
+}
+
+// SetFormat Sets whether messages are printed as plain text or as JSON lines
+func SetFormat(format Format) {
+	currentFormat = format
+}
+
+// GetFormat Returns the format messages are printed in
+func GetFormat() Format {
+	return currentFormat
+}
+
+// ParseFormat Converts the value of the log-format flag to a Format
+func ParseFormat(format string) (Format, error) {
+
+	switch format {
+	case "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	}
+
+	return FormatText, fmt.Errorf("invalid log format %q. Valid formats are text and json", format)
+}
+
+// SetSite Sets the site name included in JSON output
+func SetSite(site string) {
+	currentSite = site
+}
+
+// Debug Prints a message that is only useful when troubleshooting
+func Debug(format string, a ...interface{}) {
+	printMessage(LevelDebug, os.Stdout, format, a...)
+}
+
+// Info Prints an informational message such as the progress of a command
+func Info(format string, a ...interface{}) {
+	printMessage(LevelInfo, os.Stdout, format, a...)
+}
+
+// Warn Prints a message about something that didn't stop the command but that the user should know about
+func Warn(format string, a ...interface{}) {
+	printMessage(LevelWarn, os.Stderr, format, a...)
+}
+
+// Error Prints an error. Errors are always printed
+func Error(err error) {
+
+	if currentFormat == FormatJSON {
+
+		var fields map[string]interface{}
+		var fielder Fielder
+
+		if errors.As(err, &fielder) {
+			fields = fielder.Fields()
+		}
+
+		printJSON(LevelError, os.Stderr, err.Error(), fields)
+
+		return
+	}
+
+	writeLine(os.Stderr, err.Error())
+}
+
+func printMessage(level Level, writer io.Writer, format string, a ...interface{}) {
+
+	if !IsEnabled(level) {
+		return
+	}
+
+	if currentFormat == FormatJSON {
+		printJSON(level, writer, fmt.Sprintf(format, a...), nil)
+		return
+	}
+
+	writeLine(writer, fmt.Sprintf(format, a...))
+}
+
+// printJSON Writes a single log entry as a line of JSON
+func printJSON(level Level, writer io.Writer, message string, fields map[string]interface{}) {
+
+	entry := logEntry{
+		Level:     levelNames[level],
+		Message:   message,
+		Site:      currentSite,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Fields:    fields,
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		fmt.Fprintln(writer, message)
+		return
+	}
+
+	fmt.Fprintln(writer, string(line))
+}
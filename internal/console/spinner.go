@@ -0,0 +1,129 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// spinnerLock Keeps messages and the spinner from writing to the terminal at the same time
+var spinnerLock sync.Mutex
+var spinnerActive bool
+
+// Spinner Shows an animated indicator next to a message while a long operation runs
+type Spinner struct {
+	message string
+	stop    chan bool
+	done    chan bool
+}
+
+// IsInteractive Returns true if info messages are printed as text to a terminal, where they can be redrawn in place
+func IsInteractive() bool {
+
+	if !IsEnabled(LevelInfo) || currentFormat != FormatText {
+		return false
+	}
+
+	fileInfo, err := os.Stdout.Stat()
+
+	return err == nil && fileInfo.Mode()&os.ModeCharDevice != 0
+}
+
+// StartSpinner Prints the message with an animated indicator until Stop is called. When the output isn't a
+// terminal the message is printed once like any other info message.
+func StartSpinner(format string, a ...interface{}) *Spinner {
+
+	spinner := &Spinner{
+		message: fmt.Sprintf(format, a...),
+	}
+
+	if !IsInteractive() {
+		Info("%s", spinner.message)
+		return spinner
+	}
+
+	spinner.stop = make(chan bool)
+	spinner.done = make(chan bool)
+
+	go spinner.run()
+
+	return spinner
+}
+
+func (s *Spinner) run() {
+
+	cursor := Cursor{}
+	ticker := time.NewTicker(100 * time.Millisecond)
+
+	defer ticker.Stop()
+
+	spinnerLock.Lock()
+	spinnerActive = true
+	cursor.Hide()
+	spinnerLock.Unlock()
+
+	for frame := 0; ; frame++ {
+
+		spinnerLock.Lock()
+		// Clear the line first as the message may have been replaced with a shorter one
+		fmt.Printf("\r")
+		cursor.ClearLine()
+		fmt.Printf("%s %s", spinnerFrames[frame%len(spinnerFrames)], s.message)
+		spinnerLock.Unlock()
+
+		select {
+		case <-s.stop:
+			spinnerLock.Lock()
+			fmt.Printf("\r")
+			cursor.ClearLine()
+			cursor.Show()
+			spinnerActive = false
+			spinnerLock.Unlock()
+
+			close(s.done)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeLine Writes a line of text, first clearing the line of a running spinner, which is drawn again below it
+func writeLine(writer io.Writer, text string) {
+
+	spinnerLock.Lock()
+	defer spinnerLock.Unlock()
+
+	if spinnerActive {
+		fmt.Printf("\r")
+		(&Cursor{}).ClearLine()
+	}
+
+	fmt.Fprintln(writer, text)
+}
+
+// SetMessage Replaces the message shown next to the indicator, such as to report progress. Nothing is printed when the
+// output isn't a terminal.
+func (s *Spinner) SetMessage(format string, a ...interface{}) {
+
+	spinnerLock.Lock()
+	defer spinnerLock.Unlock()
+
+	s.message = fmt.Sprintf(format, a...)
+}
+
+// Stop Ends the animation and clears its line so the next message is printed in its place
+func (s *Spinner) Stop() {
+
+	if s.stop == nil {
+		return
+	}
+
+	close(s.stop)
+	<-s.done
+
+	s.stop = nil
+}
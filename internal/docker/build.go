@@ -0,0 +1,193 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+)
+
+type buildEvent struct {
+	Stream string `json:"stream"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BuildImage Builds the Dockerfile, using its folder as the build context, into an image with the given tag.
+// The build is skipped if an image with the tag already exists.
+func (d *DockerClient) BuildImage(dockerfile, tag string, buildArgs map[string]*string) error {
+
+	_, _, err := d.client.ImageInspectWithRaw(context.Background(), tag)
+	if err == nil {
+		return nil
+	}
+
+	if !client.IsErrNotFound(err) {
+		return newOperationError("image inspect", "", err)
+	}
+
+	buildContext, err := createBuildContext(filepath.Dir(dockerfile), filepath.Base(dockerfile))
+	if err != nil {
+		return err
+	}
+	defer buildContext.Close()
+
+	console.Info("Building image %s from %s", tag, dockerfile)
+
+	response, err := d.client.ImageBuild(context.Background(), buildContext, types.ImageBuildOptions{
+		Dockerfile:  filepath.Base(dockerfile),
+		Tags:        []string{tag},
+		BuildArgs:   buildArgs,
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		return newOperationError("image build", "", err)
+	}
+	defer response.Body.Close()
+
+	decoder := json.NewDecoder(response.Body)
+
+	for {
+
+		var event buildEvent
+
+		err := decoder.Decode(&event)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return err
+		}
+
+		if len(event.Error) > 0 {
+			return fmt.Errorf("unable to build %s: %s", dockerfile, event.Error)
+		}
+
+		if output := strings.TrimRight(event.Stream, "\n"); len(output) > 0 {
+			console.Info("%s", output)
+		}
+	}
+
+	return nil
+}
+
+// readDockerignore Returns the patterns in the folder's .dockerignore file, if it has one
+func readDockerignore(directory string) (*patternmatcher.PatternMatcher, error) {
+
+	ignoreFile, err := os.Open(filepath.Join(directory, ".dockerignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return patternmatcher.New([]string{})
+		}
+
+		return nil, err
+	}
+	defer ignoreFile.Close()
+
+	patterns, err := ignorefile.ReadAll(ignoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %s", ignoreFile.Name(), err)
+	}
+
+	return patternmatcher.New(patterns)
+}
+
+// createBuildContext Returns a stream of the folder as a tar archive to send to Docker as the build context.
+// Files matching the folder's .dockerignore are left out, except for the Dockerfile and the .dockerignore itself which Docker always needs.
+func createBuildContext(directory, dockerfile string) (io.ReadCloser, error) {
+
+	ignored, err := readDockerignore(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		pipeWriter.CloseWithError(writeBuildContext(pipeWriter, directory, dockerfile, ignored))
+	}()
+
+	return pipeReader, nil
+}
+
+// writeBuildContext Writes the folder, minus any ignored files, to the writer as a tar archive
+func writeBuildContext(writer io.Writer, directory, dockerfile string, ignored *patternmatcher.PatternMatcher) error {
+
+	tarWriter := tar.NewWriter(writer)
+
+	err := filepath.Walk(directory, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(directory, file)
+		if err != nil || relativePath == "." {
+			return err
+		}
+
+		if relativePath != dockerfile && relativePath != ".dockerignore" {
+
+			skip, err := ignored.MatchesOrParentMatches(relativePath)
+			if err != nil {
+				return err
+			}
+
+			if skip {
+				// A folder can only be skipped entirely if no "!" pattern might add back something inside it
+				if info.IsDir() && !ignored.Exclusions() {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+		}
+
+		// Symlinks and other special files can't be sent as part of the context
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(relativePath)
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		contents, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer contents.Close()
+
+		_, err = io.Copy(tarWriter, contents)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tarWriter.Close()
+}
@@ -0,0 +1,73 @@
+package docker
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCreateBuildContext(t *testing.T) {
+
+	directory := t.TempDir()
+
+	files := map[string]string{
+		"Dockerfile":         "FROM alpine",
+		".dockerignore":      "Dockerfile\nnode_modules\n*.log\n!keep.log\n",
+		"entrypoint.sh":      "#!/bin/sh",
+		"debug.log":          "debug",
+		"keep.log":           "keep",
+		"node_modules/a.js":  "a",
+		"config/php.ini":     "memory_limit = 512M",
+		"config/sub/app.log": "app",
+	}
+
+	for name, contents := range files {
+
+		file := filepath.Join(directory, filepath.FromSlash(name))
+
+		err := os.MkdirAll(filepath.Dir(file), 0750)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(file, []byte(contents), 0640)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buildContext, err := createBuildContext(directory, "Dockerfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buildContext.Close()
+
+	received := []string{}
+	tarReader := tar.NewReader(buildContext)
+
+	for {
+
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		received = append(received, header.Name)
+	}
+
+	sort.Strings(received)
+
+	expected := []string{".dockerignore", "Dockerfile", "config", "config/php.ini", "config/sub", "config/sub/app.log", "entrypoint.sh", "keep.log"}
+
+	if strings.Join(received, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v; received %v\n", expected, received)
+	}
+}
@@ -3,11 +3,17 @@ package docker
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -18,15 +24,77 @@ import (
 )
 
 type ContainerConfig struct {
-	Name        string
-	Image       string
-	Ports       []ExposedPorts
-	HostName    string
-	NetworkName string
-	Volumes     []mount.Mount
-	Command     []string
-	Env         []string
-	Labels      map[string]string
+	Name           string
+	Image          string
+	Ports          []ExposedPorts
+	HostName       string
+	NetworkName    string
+	NetworkAliases []string
+	Volumes        []mount.Mount
+	Entrypoint     []string
+	Command        []string
+	Env            []string
+	Labels         map[string]string
+	ExtraHosts     []string
+	DependsOn      []string
+	WorkingDir     string
+	User           string
+	VolumesFrom    []string
+}
+
+// SortContainers Orders the given containers so each one comes after the containers it depends on.
+// Containers without a dependency between them keep their original order.
+func SortContainers(containers []ContainerConfig) ([]ContainerConfig, error) {
+
+	containerNames := make(map[string]bool, len(containers))
+
+	for _, container := range containers {
+		containerNames[container.Name] = true
+	}
+
+	for _, container := range containers {
+		for _, dependency := range container.DependsOn {
+			if !containerNames[dependency] {
+				return nil, fmt.Errorf("container %s depends on unknown container %s", container.Name, dependency)
+			}
+		}
+	}
+
+	sorted := make([]ContainerConfig, 0, len(containers))
+	added := make(map[string]bool, len(containers))
+
+	for len(sorted) < len(containers) {
+
+		progress := false
+
+		for _, container := range containers {
+
+			if added[container.Name] {
+				continue
+			}
+
+			ready := true
+
+			for _, dependency := range container.DependsOn {
+				if !added[dependency] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				sorted = append(sorted, container)
+				added[container.Name] = true
+				progress = true
+			}
+		}
+
+		if !progress {
+			return nil, fmt.Errorf("unable to order containers. There is a circular dependency")
+		}
+	}
+
+	return sorted, nil
 }
 
 type ExecResult struct {
@@ -35,18 +103,34 @@ type ExecResult struct {
 	ExitCode int
 }
 
-// ListContainers Lists all running containers for a given site or all sites if no site is specified
-func (d *DockerClient) ListContainers(site string) ([]string, error) {
+// StopOrder Orders the given containers so each one is stopped before the containers it depends on,
+// the reverse of the order SortContainers starts them in
+func StopOrder(containers []ContainerConfig) ([]ContainerConfig, error) {
+
+	sorted, err := SortContainers(containers)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+		sorted[i], sorted[j] = sorted[j], sorted[i]
+	}
+
+	return sorted, nil
+}
+
+// ListContainers Lists all containers with the given site label for a given site or all sites if no site is specified
+func (d *DockerClient) ListContainers(siteLabel, site string) ([]string, error) {
 
 	f := filters.NewArgs()
 
 	if len(site) == 0 {
 
-		f.Add("label", "kana.site")
+		f.Add("label", siteLabel)
 
 	} else {
 
-		f.Add("label", fmt.Sprintf("kana.site=%s", site))
+		f.Add("label", fmt.Sprintf("%s=%s", siteLabel, site))
 
 	}
 
@@ -72,6 +156,41 @@ func (d *DockerClient) ListContainers(site string) ([]string, error) {
 	return containerIds, nil
 }
 
+// ListContainerLabelValues Lists the unique values of the given label across all containers that have it
+func (d *DockerClient) ListContainerLabelValues(label string) ([]string, error) {
+
+	f := filters.NewArgs()
+	f.Add("label", label)
+
+	options := types.ContainerListOptions{
+		All:     true,
+		Filters: f,
+	}
+
+	containers, err := d.client.ContainerList(
+		context.Background(),
+		options)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	values := []string{}
+	found := make(map[string]bool)
+
+	for _, container := range containers {
+
+		value := container.Labels[label]
+
+		if !found[value] {
+			found[value] = true
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
 // IsContainerRunning Checks if a given container is running by name
 func (d *DockerClient) IsContainerRunning(containerName string) (id string, isRunning bool) {
 
@@ -104,11 +223,82 @@ func (d *DockerClient) ContainerGetMounts(containerName string) []types.MountPoi
 	return results.Mounts
 }
 
+// ContainerGetLabels Returns the labels of the given container
+func (d *DockerClient) ContainerGetLabels(containerName string) map[string]string {
+
+	containerID, isRunning := d.IsContainerRunning(containerName)
+	if !isRunning {
+		return map[string]string{}
+	}
+
+	results, _ := d.client.ContainerInspect(context.Background(), containerID)
+
+	return results.Config.Labels
+}
+
+// ContainerGetHostPort Returns the host port the given container port is bound to or an empty string if it isn't bound
+func (d *DockerClient) ContainerGetHostPort(containerName, containerPort string) string {
+
+	containerID, isRunning := d.IsContainerRunning(containerName)
+	if !isRunning {
+		return ""
+	}
+
+	results, err := d.client.ContainerInspect(context.Background(), containerID)
+	if err != nil || results.NetworkSettings == nil {
+		return ""
+	}
+
+	for port, bindings := range results.NetworkSettings.Ports {
+		if port.Port() == containerPort && len(bindings) > 0 {
+			return bindings[0].HostPort
+		}
+	}
+
+	return ""
+}
+
+// ContainerReceivedBytes Returns the total number of bytes the given container has received over the network
+func (d *DockerClient) ContainerReceivedBytes(containerName string) (uint64, error) {
+
+	containerID, isRunning := d.IsContainerRunning(containerName)
+	if !isRunning {
+		return 0, fmt.Errorf("container %s is not running", containerName)
+	}
+
+	stats, err := d.client.ContainerStatsOneShot(context.Background(), containerID)
+	if err != nil {
+		return 0, err
+	}
+	defer stats.Body.Close()
+
+	var statsJSON types.StatsJSON
+
+	err = json.NewDecoder(stats.Body).Decode(&statsJSON)
+	if err != nil {
+		return 0, err
+	}
+
+	var receivedBytes uint64
+
+	for _, networkStats := range statsJSON.Networks {
+		receivedBytes += networkStats.RxBytes
+	}
+
+	return receivedBytes, nil
+}
+
 func (d *DockerClient) ContainerRun(config ContainerConfig) (id string, err error) {
 
 	containerID, isRunning := d.IsContainerRunning(config.Name)
 	if isRunning {
-		return containerID, nil
+		return containerID, d.verifyContainerImage(containerID, config)
+	}
+
+	// A container left stopped, such as after the host restarts, would block creating a new one with the same name
+	err = d.removeStoppedContainer(config.Name)
+	if err != nil {
+		return "", err
 	}
 
 	hostConfig := container.HostConfig{}
@@ -122,29 +312,36 @@ func (d *DockerClient) ContainerRun(config ContainerConfig) (id string, err erro
 
 	if len(config.NetworkName) > 0 {
 		networkConfig.EndpointsConfig = map[string]*network.EndpointSettings{
-			config.NetworkName: {},
+			config.NetworkName: {
+				Aliases: config.NetworkAliases,
+			},
 		}
 	}
 
 	hostConfig.Mounts = config.Volumes
+	hostConfig.VolumesFrom = config.VolumesFrom
+	hostConfig.ExtraHosts = config.ExtraHosts
 
 	resp, err := d.client.ContainerCreate(context.Background(), &container.Config{
 		Tty:          true,
 		Image:        config.Image,
 		ExposedPorts: containerPorts.PortSet,
+		Entrypoint:   config.Entrypoint,
 		Cmd:          config.Command,
 		Hostname:     config.HostName,
 		Env:          config.Env,
 		Labels:       config.Labels,
+		WorkingDir:   config.WorkingDir,
+		User:         config.User,
 	}, &hostConfig, &networkConfig, nil, config.Name)
 
 	if err != nil {
-		return "", err
+		return "", newOperationError("container create", config.Name, err)
 	}
 
 	err = d.client.ContainerStart(context.Background(), resp.ID, types.ContainerStartOptions{})
 	if err != nil {
-		return "", err
+		return "", newOperationError("container start", config.Name, err)
 	}
 
 	return resp.ID, nil
@@ -184,6 +381,29 @@ func (d *DockerClient) ContainerLog(id string) (result string, err error) {
 	return string(buffer), nil
 }
 
+// ContainerLogFollow Copies the logs of the given container to the writer. When follow is set it keeps copying new output until the container stops.
+func (d *DockerClient) ContainerLogFollow(containerName string, follow bool, writer io.Writer) error {
+
+	containerID, isRunning := d.IsContainerRunning(containerName)
+	if !isRunning {
+		return fmt.Errorf("container %s is not running", containerName)
+	}
+
+	reader, err := d.client.ContainerLogs(context.Background(), containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     follow,
+	})
+	if err != nil {
+		return newOperationError("container logs", containerName, err)
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(writer, reader)
+
+	return err
+}
+
 func (d *DockerClient) ContainerRunAndClean(config ContainerConfig) (statusCode int64, body string, err error) {
 
 	// Start the container
@@ -192,10 +412,12 @@ func (d *DockerClient) ContainerRunAndClean(config ContainerConfig) (statusCode
 		return statusCode, body, err
 	}
 
+	defer d.removeOnInterrupt(id)()
+
 	// Wait for it to finish
 	statusCode, err = d.ContainerWait(id)
 	if err != nil {
-		return statusCode, body, err
+		return statusCode, body, newOperationError("container wait", config.Name, err)
 	}
 
 	// Get the log
@@ -204,12 +426,106 @@ func (d *DockerClient) ContainerRunAndClean(config ContainerConfig) (statusCode
 	err = d.client.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{})
 
 	if err != nil {
-		fmt.Printf("Unable to remove container %q: %q\n", id, err)
+		console.Warn("Unable to remove container %q: %q", id, err)
 	}
 
 	return statusCode, body, err
 }
 
+// ContainerRunAndFollow Runs the container, copying its output to the writer as it is printed, and removes it once
+// it finishes. Returns the container's exit code.
+func (d *DockerClient) ContainerRunAndFollow(config ContainerConfig, writer io.Writer) (statusCode int64, err error) {
+
+	id, err := d.ContainerRun(config)
+	if err != nil {
+		return statusCode, err
+	}
+
+	defer d.removeOnInterrupt(id)()
+
+	// Following the logs only ends once the container stops
+	reader, err := d.client.ContainerLogs(context.Background(), id, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err == nil {
+		_, err = io.Copy(writer, reader)
+		reader.Close()
+	} else {
+		err = newOperationError("container logs", config.Name, err)
+	}
+
+	if err == nil {
+		statusCode, err = d.ContainerWait(id)
+		if err != nil {
+			err = newOperationError("container wait", config.Name, err)
+		}
+	}
+
+	// The container is removed even if its output couldn't be read so it doesn't block the next command
+	removeErr := d.client.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{Force: true})
+	if removeErr != nil {
+		console.Warn("Unable to remove container %q: %q", id, removeErr)
+	}
+
+	return statusCode, err
+}
+
+// removeOnInterrupt Makes sure the container is removed if the command is interrupted until the returned function
+// is called
+func (d *DockerClient) removeOnInterrupt(id string) func() {
+
+	interrupt := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-interrupt:
+			_ = d.client.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{Force: true})
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(interrupt)
+		close(done)
+	}
+}
+
+// removeStoppedContainer Removes the named container if it exists but isn't running
+func (d *DockerClient) removeStoppedContainer(containerName string) error {
+
+	if len(containerName) == 0 {
+		return nil
+	}
+
+	f := filters.NewArgs()
+	f.Add("name", fmt.Sprintf("^/%s$", containerName))
+
+	containers, err := d.client.ContainerList(context.Background(), types.ContainerListOptions{
+		All:     true,
+		Filters: f,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
+		if container.State != "running" {
+			err = d.client.ContainerRemove(context.Background(), container.ID, types.ContainerRemoveOptions{Force: true})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (d *DockerClient) ContainerStop(containerName string) (bool, error) {
 
 	containerID, isRunning := d.IsContainerRunning(containerName)
@@ -219,17 +535,28 @@ func (d *DockerClient) ContainerStop(containerName string) (bool, error) {
 
 	err := d.client.ContainerStop(context.Background(), containerID, nil)
 	if err != nil {
-		return false, err
+		return false, newOperationError("container stop", containerName, err)
 	}
 
 	err = d.client.ContainerRemove(context.Background(), containerID, types.ContainerRemoveOptions{})
 	if err != nil {
-		return false, err
+		return false, newOperationError("container remove", containerName, err)
 	}
 
 	return true, nil
 }
 
+// ContainerRemove Stops and removes the container with the given ID or name, whether it is running or not
+func (d *DockerClient) ContainerRemove(container string) error {
+
+	err := d.client.ContainerRemove(context.Background(), container, types.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		return newOperationError("container remove", container, err)
+	}
+
+	return nil
+}
+
 func (d *DockerClient) ContainerRestart(containerName string) (bool, error) {
 
 	containerID, isRunning := d.IsContainerRunning(containerName)
@@ -239,18 +566,62 @@ func (d *DockerClient) ContainerRestart(containerName string) (bool, error) {
 
 	err := d.client.ContainerStop(context.Background(), containerID, nil)
 	if err != nil {
-		return false, err
+		return false, newOperationError("container stop", containerName, err)
 	}
 
 	err = d.client.ContainerStart(context.Background(), containerID, types.ContainerStartOptions{})
 	if err != nil {
-		return false, err
+		return false, newOperationError("container start", containerName, err)
 	}
 
 	return true, nil
 }
 
-func (d *DockerClient) ContainerExec(containerName string, command []string) (ExecResult, error) {
+// ContainerPause Freezes the processes of the running container so it stops using CPU while keeping its state
+func (d *DockerClient) ContainerPause(containerName string) (bool, error) {
+
+	containerID, isRunning := d.IsContainerRunning(containerName)
+	if !isRunning || d.IsContainerPaused(containerName) {
+		return true, nil
+	}
+
+	err := d.client.ContainerPause(context.Background(), containerID)
+	if err != nil {
+		return false, newOperationError("container pause", containerName, err)
+	}
+
+	return true, nil
+}
+
+// ContainerUnpause Resumes the processes of a container frozen with ContainerPause
+func (d *DockerClient) ContainerUnpause(containerName string) (bool, error) {
+
+	containerID, isRunning := d.IsContainerRunning(containerName)
+	if !isRunning || !d.IsContainerPaused(containerName) {
+		return true, nil
+	}
+
+	err := d.client.ContainerUnpause(context.Background(), containerID)
+	if err != nil {
+		return false, newOperationError("container unpause", containerName, err)
+	}
+
+	return true, nil
+}
+
+// IsContainerPaused Returns true if the container is running but paused
+func (d *DockerClient) IsContainerPaused(containerName string) bool {
+
+	containerInfo, err := d.client.ContainerInspect(context.Background(), containerName)
+	if err != nil || containerInfo.State == nil {
+		return false
+	}
+
+	return containerInfo.State.Paused
+}
+
+// ContainerExec Runs the command in the running container as the given user, or the container's own user if it is empty
+func (d *DockerClient) ContainerExec(containerName, user string, command []string) (ExecResult, error) {
 
 	containerID, isRunning := d.IsContainerRunning(containerName)
 	if !isRunning {
@@ -269,11 +640,12 @@ func (d *DockerClient) ContainerExec(containerName string, command []string) (Ex
 		AttachStdout: true,
 		AttachStderr: true,
 		Cmd:          strslice.StrSlice(fullCommand),
+		User:         user,
 	}
 
 	cresp, err := d.client.ContainerExecCreate(context.Background(), containerID, execConfig)
 	if err != nil {
-		return ExecResult{}, err
+		return ExecResult{}, newOperationError("container exec", containerName, err)
 	}
 
 	execID := cresp.ID
@@ -281,7 +653,7 @@ func (d *DockerClient) ContainerExec(containerName string, command []string) (Ex
 	// run it, with stdout/stderr attached
 	aresp, err := d.client.ContainerExecAttach(context.Background(), execID, types.ExecStartCheck{})
 	if err != nil {
-		return ExecResult{}, err
+		return ExecResult{}, newOperationError("container exec", containerName, err)
 	}
 
 	defer aresp.Close()
@@ -310,7 +682,7 @@ func (d *DockerClient) ContainerExec(containerName string, command []string) (Ex
 	// get the exit code
 	iresp, err := d.client.ContainerExecInspect(context.Background(), execID)
 	if err != nil {
-		return ExecResult{}, err
+		return ExecResult{}, newOperationError("container exec", containerName, err)
 	}
 
 	return ExecResult{
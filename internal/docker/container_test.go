@@ -42,3 +42,86 @@ func TestContainerRun(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestSortContainers(t *testing.T) {
+
+	containers := []ContainerConfig{
+		{Name: "wordpress", DependsOn: []string{"database", "cache"}},
+		{Name: "mail"},
+		{Name: "database"},
+		{Name: "cache", DependsOn: []string{"database"}},
+	}
+
+	sorted, err := SortContainers(containers)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	expected := []string{"mail", "database", "cache", "wordpress"}
+
+	if len(sorted) != len(expected) {
+		t.Fatalf("Expected %d containers; received %d\n", len(expected), len(sorted))
+	}
+
+	for i, container := range sorted {
+		if container.Name != expected[i] {
+			t.Errorf("Expected %q at position %d; received %q\n", expected[i], i, container.Name)
+		}
+	}
+}
+
+func TestStopOrder(t *testing.T) {
+
+	containers := []ContainerConfig{
+		{Name: "database"},
+		{Name: "wordpress", DependsOn: []string{"database"}},
+		{Name: "wordpress_php81", DependsOn: []string{"database"}},
+		{Name: "cache", DependsOn: []string{"database"}},
+		{Name: "mail", DependsOn: []string{"wordpress"}},
+	}
+
+	stopped, err := StopOrder(containers)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	expected := []string{"mail", "cache", "wordpress_php81", "wordpress", "database"}
+
+	if len(stopped) != len(expected) {
+		t.Fatalf("Expected %d containers; received %d\n", len(expected), len(stopped))
+	}
+
+	for i, container := range stopped {
+		if container.Name != expected[i] {
+			t.Errorf("Expected %q at position %d; received %q\n", expected[i], i, container.Name)
+		}
+	}
+}
+
+func TestSortContainersUnknownDependency(t *testing.T) {
+
+	containers := []ContainerConfig{
+		{Name: "wordpress", DependsOn: []string{"database"}},
+	}
+
+	_, err := SortContainers(containers)
+	if err == nil {
+		t.Error("Expected an error for an unknown dependency")
+	}
+}
+
+func TestSortContainersCircularDependency(t *testing.T) {
+
+	containers := []ContainerConfig{
+		{Name: "database"},
+		{Name: "wordpress", DependsOn: []string{"cache"}},
+		{Name: "cache", DependsOn: []string{"wordpress"}},
+	}
+
+	_, err := SortContainers(containers)
+	if err == nil {
+		t.Error("Expected an error for a circular dependency")
+	}
+}
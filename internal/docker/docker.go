@@ -7,10 +7,13 @@ package docker
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
 	"time"
 
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
 )
@@ -19,15 +22,49 @@ type DockerClient struct {
 	client *client.Client
 }
 
+// NewController Returns a client for the Docker host set with SetHost or, if none is set, the one in DOCKER_HOST.
+// Without either the default socket is used, falling back to the sockets of rootless Docker and Docker Desktop
+// alternatives if it can't be reached.
 func NewController() (c *DockerClient, err error) {
 
+	if len(configuredHost) > 0 {
+		c, err = NewRemoteController(configuredHost)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isReachable(c.client) {
+			return nil, fmt.Errorf("unable to connect to the Docker host %s. Please check the docker.host setting", configuredHost)
+		}
+
+		resolvedHost = configuredHost
+
+		return c, nil
+	}
+
 	c = new(DockerClient)
 
-	c.client, err = client.NewClientWithOpts(client.FromEnv)
+	options := []client.Opt{client.FromEnv}
+
+	if len(resolvedHost) > 0 {
+		options = append(options, client.WithHost(resolvedHost))
+	}
+
+	c.client, err = client.NewClientWithOpts(options...)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(resolvedHost) == 0 {
+
+		// DOCKER_HOST is the user's own choice so only the default socket falls back to the others
+		if len(os.Getenv("DOCKER_HOST")) == 0 && !isReachable(c.client) {
+			c.client = detectSocketClient(c.client)
+		}
+
+		resolvedHost = c.client.DaemonHost()
+	}
+
 	err = c.ensureDockerIsAvailable()
 	if err != nil {
 		return nil, err
@@ -42,7 +79,7 @@ func (d *DockerClient) ensureDockerIsAvailable() error {
 	if err != nil {
 		if runtime.GOOS == "darwin" {
 
-			fmt.Println("Docker doesn't appear to be running. Trying to start Docker.")
+			console.Info("Docker doesn't appear to be running. Trying to start Docker.")
 			err = exec.Command("open", "-a", "Docker").Run()
 			if err != nil {
 				return fmt.Errorf("error: unable to start Docker for Mac")
@@ -55,7 +92,7 @@ func (d *DockerClient) ensureDockerIsAvailable() error {
 				retries++
 
 				if retries == 12 {
-					fmt.Println("Restarting Docker is taking too long. We seem to have hit an error")
+					console.Warn("Restarting Docker is taking too long. We seem to have hit an error")
 					return fmt.Errorf("error: unable to start Docker for Mac")
 				}
 
@@ -0,0 +1,46 @@
+package docker
+
+// OperationError Wraps an error returned by Docker with the operation and container it came from
+type OperationError struct {
+	Operation string
+	Container string
+	Err       error
+}
+
+// newOperationError Wraps err in an OperationError. Returns nil if err is nil
+func newOperationError(operation, containerName string, err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	return &OperationError{
+		Operation: operation,
+		Container: containerName,
+		Err:       err,
+	}
+}
+
+// Error Returns the message of the original error so text output is unchanged
+func (e *OperationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap Returns the original error
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Fields Returns the operation and container for structured logs
+func (e *OperationError) Fields() map[string]interface{} {
+
+	fields := map[string]interface{}{
+		"operation": e.Operation,
+	}
+
+	if len(e.Container) > 0 {
+		fields["container"] = e.Container
+	}
+
+	return fields
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	"github.com/ChrisWiegman/kana-cli/internal/console"
 
@@ -23,20 +24,160 @@ type pullEvent struct {
 	} `json:"progressDetail"`
 }
 
+// pullOutput Is how much ensureImage prints while pulling an image
+type pullOutput int
+
+const (
+	pullSilent pullOutput = iota
+	pullMessage
+	pullProgress
+	pullTracked
+)
+
+// maxConcurrentPulls Is how many images EnsureImages pulls at once
+const maxConcurrentPulls = 3
+
+// pullTracker Combines the progress of images pulled at the same time into a single spinner message
+type pullTracker struct {
+	mutex   sync.Mutex
+	spinner *console.Spinner
+	images  int
+	done    int
+	current map[string]int
+	total   map[string]int
+}
+
+// imageLocks Holds a mutex for each image so the same image is never pulled twice at once
+var imageLocks sync.Map
+
+func (d *DockerClient) EnsureImage(imageName string) (err error) {
+	// The progress display redraws lines in place so it needs a terminal and can't be used with structured output
+	if console.IsInteractive() {
+		return d.ensureImage(imageName, pullProgress, nil)
+	}
+
+	return d.ensureImage(imageName, pullMessage, nil)
+}
+
+// EnsureImages Pulls any of the images that are missing, several at a time, showing their combined progress.
+// Returns the first error in the order the images were given.
+func (d *DockerClient) EnsureImages(imageNames []string) error {
+
+	uniqueImages := []string{}
+	seen := make(map[string]bool)
+
+	for _, imageName := range imageNames {
+
+		key := imageName
+		if !strings.Contains(imageName, ":") {
+			key = fmt.Sprintf("%s:latest", imageName)
+		}
+
+		if !seen[key] {
+			seen[key] = true
+			uniqueImages = append(uniqueImages, imageName)
+		}
+	}
+
+	// A single image keeps the detailed progress of each layer
+	if len(uniqueImages) == 1 {
+		return d.EnsureImage(uniqueImages[0])
+	}
+
+	output := pullMessage
+	var tracker *pullTracker
+
+	if console.IsInteractive() {
+		output = pullTracked
+		tracker = &pullTracker{
+			images:  len(uniqueImages),
+			current: make(map[string]int),
+			total:   make(map[string]int),
+		}
+		tracker.spinner = console.StartSpinner("Checking %d images...", len(uniqueImages))
+		defer tracker.spinner.Stop()
+	}
+
+	errors := make([]error, len(uniqueImages))
+	jobs := make(chan int)
+
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < maxConcurrentPulls && worker < len(uniqueImages); worker++ {
+
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for i := range jobs {
+				errors[i] = d.ensureImage(uniqueImages[i], output, tracker)
+
+				if tracker != nil {
+					tracker.finish()
+				}
+			}
+		}()
+	}
+
+	for i := range uniqueImages {
+		jobs <- i
+	}
+
+	close(jobs)
+	waitGroup.Wait()
+
+	for _, err := range errors {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnsureImageInBackground Starts pulling the image if it's missing without showing any progress.
+// Calls to EnsureImage for the same image wait for the pull to finish.
+func (d *DockerClient) EnsureImageInBackground(imageName string) {
+
+	go func() {
+		err := d.ensureImage(imageName, pullSilent, nil)
+		if err != nil {
+			console.Debug("Unable to pull %s in the background: %s", imageName, err)
+		}
+	}()
+}
+
 // https://gist.github.com/miguelmota/4980b18d750fb3b1eb571c3e207b1b92
 // https://riptutorial.com/docker/example/31980/image-pulling-with-progress-bars--written-in-go
-func (d *DockerClient) EnsureImage(imageName string) (err error) {
+func (d *DockerClient) ensureImage(imageName string, output pullOutput, tracker *pullTracker) (err error) {
+
+	_, digest := splitImageDigest(imageName)
 
 	if !strings.Contains(imageName, ":") {
 		imageName = fmt.Sprintf("%s:latest", imageName)
 	}
 
+	lock, _ := imageLocks.LoadOrStore(imageName, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
 	images, err := d.client.ImageList(context.Background(), types.ImageListOptions{})
 	if err != nil {
 		return err
 	}
 
 	for _, image := range images {
+
+		// Images pinned to a digest match on the digest alone as tags can be moved to other images
+		if len(digest) > 0 {
+			if hasRepoDigest(image.RepoDigests, digest) {
+				return nil
+			}
+
+			continue
+		}
+
 		for _, imageTag := range image.RepoTags {
 			if imageTag == imageName {
 				return nil
@@ -46,11 +187,17 @@ func (d *DockerClient) EnsureImage(imageName string) (err error) {
 
 	events, err := d.client.ImagePull(context.Background(), imageName, types.ImagePullOptions{})
 	if err != nil {
-		return err
+		return newOperationError("image pull", "", err)
 	}
 
 	defer events.Close()
 
+	if output == pullMessage {
+		console.Info("Pulling %s...", imageName)
+	}
+
+	showProgress := output == pullProgress
+
 	cursor := console.Cursor{}
 	layers := make([]string, 0)
 	oldIndex := len(layers)
@@ -58,7 +205,9 @@ func (d *DockerClient) EnsureImage(imageName string) (err error) {
 	var event *pullEvent
 	decoder := json.NewDecoder(events)
 
-	cursor.Hide()
+	if showProgress {
+		cursor.Hide()
+	}
 
 	for {
 
@@ -72,6 +221,15 @@ func (d *DockerClient) EnsureImage(imageName string) (err error) {
 
 		}
 
+		if output == pullTracked {
+			tracker.update(imageName, event)
+			continue
+		}
+
+		if !showProgress {
+			continue
+		}
+
 		imageID := event.ID
 
 		// Check if the line is one of the final two ones
@@ -122,7 +280,138 @@ func (d *DockerClient) EnsureImage(imageName string) (err error) {
 		}
 	}
 
-	cursor.Show()
+	if showProgress {
+		cursor.Show()
+	}
+
+	if len(digest) > 0 {
+		return d.verifyImageDigest(imageName, digest)
+	}
+
+	return nil
+}
+
+// update Records the progress of a layer of the image and refreshes the combined progress
+func (t *pullTracker) update(imageName string, event *pullEvent) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	layer := fmt.Sprintf("%s/%s", imageName, event.ID)
+
+	switch event.Status {
+	case "Downloading":
+		t.current[layer] = event.ProgressDetail.Current
+		t.total[layer] = event.ProgressDetail.Total
+	case "Download complete", "Pull complete":
+		t.current[layer] = t.total[layer]
+	}
+
+	t.refresh()
+}
+
+// finish Records that an image is ready and refreshes the combined progress
+func (t *pullTracker) finish() {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.done++
+
+	t.refresh()
+}
+
+// refresh Shows the number of images ready and how much of the layers being pulled has been downloaded
+func (t *pullTracker) refresh() {
+
+	current, total := 0, 0
+
+	for layer, layerTotal := range t.total {
+		current += t.current[layer]
+		total += layerTotal
+	}
+
+	if total == 0 {
+		t.spinner.SetMessage("Pulling images (%d of %d ready)...", t.done, t.images)
+		return
+	}
+
+	t.spinner.SetMessage("Pulling images (%d of %d ready, %d%% downloaded)...", t.done, t.images, current*100/total)
+}
+
+// splitImageDigest Splits an image such as wordpress@sha256:abc into the name and the digest it is pinned to.
+// The digest is empty if the image isn't pinned.
+func splitImageDigest(imageName string) (name, digest string) {
+
+	name, digest, _ = strings.Cut(imageName, "@")
+
+	return name, digest
+}
+
+// hasRepoDigest Returns true if any of the repo digests, in the form name@sha256:abc, has the given digest
+func hasRepoDigest(repoDigests []string, digest string) bool {
+
+	for _, repoDigest := range repoDigests {
+		if strings.HasSuffix(repoDigest, "@"+digest) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyImageDigest Returns an error if the local copy of the image doesn't have the digest it is pinned to
+func (d *DockerClient) verifyImageDigest(imageName, digest string) error {
+
+	image, _, err := d.client.ImageInspectWithRaw(context.Background(), imageName)
+	if err != nil {
+		return newOperationError("image inspect", "", err)
+	}
+
+	if !hasRepoDigest(image.RepoDigests, digest) {
+		return fmt.Errorf("the digest of image %s doesn't match the pinned digest %s", imageName, digest)
+	}
+
+	return nil
+}
+
+// GetImageCommand Returns the entrypoint and command the image runs by default
+func (d *DockerClient) GetImageCommand(imageName string) (entrypoint, command []string, err error) {
+
+	image, _, err := d.client.ImageInspectWithRaw(context.Background(), imageName)
+	if err != nil {
+		return entrypoint, command, newOperationError("image inspect", "", err)
+	}
+
+	if image.Config == nil {
+		return entrypoint, command, nil
+	}
+
+	return image.Config.Entrypoint, image.Config.Cmd, nil
+}
+
+// verifyContainerImage Returns an error if the container isn't running the image it is configured with.
+// Only images pinned to a digest are checked as a tag may have been updated since the container started.
+func (d *DockerClient) verifyContainerImage(containerID string, config ContainerConfig) error {
+
+	_, digest := splitImageDigest(config.Image)
+	if len(digest) == 0 {
+		return nil
+	}
+
+	containerInfo, err := d.client.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return newOperationError("container inspect", config.Name, err)
+	}
+
+	image, _, err := d.client.ImageInspectWithRaw(context.Background(), config.Image)
+	if err != nil {
+		return newOperationError("image inspect", config.Name, err)
+	}
+
+	if containerInfo.Image != image.ID {
+		return fmt.Errorf("container %s isn't running the pinned image %s. Stop the site and start it again to use it", config.Name, config.Image)
+	}
 
 	return nil
 }
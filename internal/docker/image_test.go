@@ -20,6 +20,22 @@ func TestEnsureImage(t *testing.T) {
 	}
 }
 
+func TestEnsureImages(t *testing.T) {
+
+	d, err := NewController()
+
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	err = d.EnsureImages([]string{"alpine", "alpine:latest", "busybox"})
+
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestRemoveImage(t *testing.T) {
 
 	d, err := NewController()
@@ -53,3 +69,39 @@ func TestRemoveImage(t *testing.T) {
 		t.Errorf("Image should not have been removed but was")
 	}
 }
+
+func TestSplitImageDigest(t *testing.T) {
+
+	tests := []struct {
+		image  string
+		name   string
+		digest string
+	}{
+		{"wordpress", "wordpress", ""},
+		{"wordpress:php8.1", "wordpress:php8.1", ""},
+		{"wordpress@sha256:abc", "wordpress", "sha256:abc"},
+		{"wordpress:php8.1@sha256:abc", "wordpress:php8.1", "sha256:abc"},
+	}
+
+	for _, test := range tests {
+
+		name, digest := splitImageDigest(test.image)
+
+		if name != test.name || digest != test.digest {
+			t.Errorf("splitImageDigest(%q) returned %q, %q. Expected %q, %q", test.image, name, digest, test.name, test.digest)
+		}
+	}
+}
+
+func TestHasRepoDigest(t *testing.T) {
+
+	repoDigests := []string{"wordpress@sha256:abc"}
+
+	if !hasRepoDigest(repoDigests, "sha256:abc") {
+		t.Errorf("The matching digest should have been found")
+	}
+
+	if hasRepoDigest(repoDigests, "sha256:ab") {
+		t.Errorf("A partial digest should not match")
+	}
+}
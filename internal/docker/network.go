@@ -3,16 +3,39 @@ package docker
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 
 	"github.com/docker/docker/api/types"
+	dockerNetwork "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
 )
 
 type ExposedPorts struct {
 	Port     string
 	Protocol string
+	HostIP   string
+	HostPort string
+}
+
+type NetworkOptions struct {
+	EnableIPv6      bool
+	IPv6Subnet      string
+	Subnet          string
+	Gateway         string
+	ManagedLabel    string
+	ContainerPrefix string
+	CreateRetries   int
+	RetryDelay      time.Duration
 }
 
+const defaultNetworkCreateRetries = 5
+const defaultNetworkRetryDelay = 200 * time.Millisecond
+
 type portConfig struct {
 	PortBindings nat.PortMap
 	PortSet      nat.PortSet
@@ -30,9 +53,16 @@ func (d *DockerClient) getNetworkConfig(ports []ExposedPorts) portConfig {
 			panic(err)
 		}
 
+		// Bind to the same port on the host unless a different one is given
+		hostPort := port.HostPort
+		if len(hostPort) == 0 {
+			hostPort = port.Port
+		}
+
 		portBindings[portName] = []nat.PortBinding{
 			{
-				HostPort: port.Port,
+				HostIP:   port.HostIP,
+				HostPort: hostPort,
 			},
 		}
 
@@ -47,26 +77,116 @@ func (d *DockerClient) getNetworkConfig(ports []ExposedPorts) portConfig {
 }
 
 func (d *DockerClient) EnsureNetwork(name string) (created bool, network types.NetworkResource, err error) {
+	return d.EnsureNetworkWithOptions(name, NetworkOptions{})
+}
 
-	hasNetwork, network, err := d.findNetworkByName(name)
+// EnsureNetworkWithOptions Creates the named bridge network with the given options if it doesn't already exist.
+// If another process creates the network at the same time the existing network is adopted instead.
+func (d *DockerClient) EnsureNetworkWithOptions(name string, options NetworkOptions) (created bool, network types.NetworkResource, err error) {
 
-	if err != nil {
-		return false, types.NetworkResource{}, err
+	retries := options.CreateRetries
+	if retries <= 0 {
+		retries = defaultNetworkCreateRetries
 	}
 
-	if hasNetwork {
-		return false, network, nil
+	retryDelay := options.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = defaultNetworkRetryDelay
+	}
+
+	// The global source isn't seeded so parallel processes would all wait the same amount of time
+	jitter := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for attempt := 0; ; attempt++ {
+
+		hasNetwork, network, err := d.findNetworkByName(name)
+
+		if err != nil {
+			return false, types.NetworkResource{}, err
+		}
+
+		if hasNetwork {
+
+			err = d.checkNetworkOwner(network, options)
+			if err != nil {
+				return false, types.NetworkResource{}, err
+			}
+
+			if network.EnableIPv6 != options.EnableIPv6 {
+				console.Warn("The %s network was created with IPv6 set to %t. Stop all sites to recreate it with the new setting.", name, network.EnableIPv6)
+			}
+
+			if len(options.Subnet) > 0 && !hasSubnet(network, options.Subnet) {
+				console.Warn("The %s network was created with a different subnet than %s. Stop all sites to recreate it with the new setting.", name, options.Subnet)
+			}
+
+			return false, network, nil
+		}
+
+		created, network, err = d.createNetwork(name, options)
+
+		// Another kana process created the network between the lookup and the create so look it up again after a random delay
+		if errdefs.IsConflict(err) && attempt < retries {
+			console.Debug("The %s network was created by another process, retrying: %s", name, err)
+			time.Sleep(retryDelay + time.Duration(jitter.Int63n(int64(retryDelay))))
+			continue
+		}
+
+		if errdefs.IsConflict(err) {
+			return false, types.NetworkResource{}, newOperationError("network create", "", err)
+		}
+
+		return created, network, err
+	}
+}
+
+// createNetwork Creates the named bridge network, returning the daemon's error as is if a network with the name already exists
+func (d *DockerClient) createNetwork(name string, options NetworkOptions) (created bool, network types.NetworkResource, err error) {
+
+	networkCreate := types.NetworkCreate{
+		Driver:         "bridge",
+		EnableIPv6:     options.EnableIPv6,
+		CheckDuplicate: true,
+	}
+
+	if len(options.ManagedLabel) > 0 {
+		networkCreate.Labels = map[string]string{
+			options.ManagedLabel: "true",
+		}
+	}
+
+	ipamConfig := []dockerNetwork.IPAMConfig{}
+
+	// Docker only picks the IPv4 range itself if no IPAM config is given at all
+	if len(options.Subnet) > 0 {
+		ipamConfig = append(ipamConfig, dockerNetwork.IPAMConfig{Subnet: options.Subnet, Gateway: options.Gateway})
+	}
+
+	if options.EnableIPv6 && len(options.IPv6Subnet) > 0 {
+		ipamConfig = append(ipamConfig, dockerNetwork.IPAMConfig{Subnet: options.IPv6Subnet})
+	}
+
+	if len(ipamConfig) > 0 {
+		networkCreate.IPAM = &dockerNetwork.IPAM{
+			Config: ipamConfig,
+		}
 	}
 
-	networkCreateResults, err := d.client.NetworkCreate(context.Background(), name, types.NetworkCreate{
-		Driver: "bridge",
-	})
+	networkCreateResults, err := d.client.NetworkCreate(context.Background(), name, networkCreate)
 
 	if err != nil {
-		return false, types.NetworkResource{}, err
+		if errdefs.IsConflict(err) {
+			return false, types.NetworkResource{}, err
+		}
+
+		if strings.Contains(err.Error(), "overlaps") {
+			return false, types.NetworkResource{}, fmt.Errorf("unable to create the %s network as its addresses overlap with another network: %s", name, err)
+		}
+
+		return false, types.NetworkResource{}, newOperationError("network create", "", err)
 	}
 
-	hasNetwork, network, err = d.findNetworkById(networkCreateResults.ID)
+	hasNetwork, network, err := d.findNetworkById(networkCreateResults.ID)
 
 	if err != nil {
 		return false, types.NetworkResource{}, err
@@ -79,6 +199,47 @@ func (d *DockerClient) EnsureNetwork(name string) (created bool, network types.N
 	return false, types.NetworkResource{}, fmt.Errorf("could not create network")
 }
 
+// checkNetworkOwner Returns an error if an existing network with the name Kana uses was created by something else.
+// Networks from before Kana labeled them are accepted as long as only Kana's containers use them.
+func (d *DockerClient) checkNetworkOwner(network types.NetworkResource, options NetworkOptions) error {
+
+	if len(options.ManagedLabel) == 0 || network.Labels[options.ManagedLabel] == "true" {
+		return nil
+	}
+
+	foreignError := fmt.Errorf("a network named %s already exists but wasn't created by Kana. Remove it or change Kana's prefix setting to use a different name", network.Name)
+
+	if len(network.Labels) > 0 {
+		return foreignError
+	}
+
+	// NetworkList doesn't include the containers attached to each network
+	networkDetails, err := d.client.NetworkInspect(context.Background(), network.ID, types.NetworkInspectOptions{})
+	if err != nil {
+		return newOperationError("network inspect", "", err)
+	}
+
+	for _, container := range networkDetails.Containers {
+		if !strings.HasPrefix(container.Name, options.ContainerPrefix) {
+			return foreignError
+		}
+	}
+
+	return nil
+}
+
+// hasSubnet Returns true if the network's addresses come from the given subnet
+func hasSubnet(network types.NetworkResource, subnet string) bool {
+
+	for _, config := range network.IPAM.Config {
+		if config.Subnet == subnet {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (d *DockerClient) RemoveNetwork(name string) (removed bool, err error) {
 
 	hasNetwork, network, err := d.findNetworkByName(name)
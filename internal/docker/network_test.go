@@ -58,3 +58,37 @@ func TestEnsureNetwork(t *testing.T) {
 	}
 
 }
+
+func TestEnsureNetworkWithSubnet(t *testing.T) {
+
+	d, err := NewController()
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	options := NetworkOptions{
+		Subnet:  "172.31.250.0/24",
+		Gateway: "172.31.250.1",
+	}
+
+	_, network, err := d.EnsureNetworkWithOptions("mynetwork", options)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !hasSubnet(network, options.Subnet) {
+		t.Errorf("Expected the network to use the subnet %s; got %v\n", options.Subnet, network.IPAM.Config)
+	}
+
+	removed, err := d.RemoveNetwork("mynetwork")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if removed != true {
+		t.Errorf("Network should have been removed but wasn't")
+	}
+}
@@ -0,0 +1,174 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// NewRemoteController Returns a client for the Docker daemon at the given host, such as tcp://dev.example.com:2376
+// or ssh://me@dev.example.com. TLS for tcp hosts is set with DOCKER_TLS_VERIFY and DOCKER_CERT_PATH like the docker CLI.
+// Hosts over ssh need the docker CLI installed on the remote machine.
+func NewRemoteController(host string) (c *DockerClient, err error) {
+
+	hostURL, err := url.Parse(host)
+	if err != nil || len(hostURL.Scheme) == 0 {
+		return nil, fmt.Errorf("invalid Docker host %q. Please use a host such as tcp://dev.example.com:2376 or ssh://me@dev.example.com", host)
+	}
+
+	options := []client.Opt{client.FromEnv}
+
+	if hostURL.Scheme == "ssh" {
+		// The host is only used to build request URLs as every connection goes through ssh
+		options = append(options, client.WithHost("http://docker.example.com"), client.WithDialContext(getSSHDialer(hostURL)))
+	} else {
+		options = append(options, client.WithHost(host))
+	}
+
+	c = new(DockerClient)
+
+	c.client, err = client.NewClientWithOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remote daemons may run an older API version than this client
+	c.client.NegotiateAPIVersion(context.Background())
+
+	return c, nil
+}
+
+// sshConnectTimeout Is how many seconds ssh waits for the remote host to answer before giving up
+const sshConnectTimeout = 15
+
+// getSSHDialer Returns a dialer that connects to the Docker daemon on the ssh host through "docker system dial-stdio"
+func getSSHDialer(hostURL *url.URL) func(ctx context.Context, network, addr string) (net.Conn, error) {
+
+	sshArgs := []string{"-o", fmt.Sprintf("ConnectTimeout=%d", sshConnectTimeout)}
+
+	if hostURL.User != nil {
+		sshArgs = append(sshArgs, "-l", hostURL.User.Username())
+	}
+
+	if len(hostURL.Port()) > 0 {
+		sshArgs = append(sshArgs, "-p", hostURL.Port())
+	}
+
+	sshArgs = append(sshArgs, "--", hostURL.Hostname(), "docker", "system", "dial-stdio")
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// The connection outlives the request that dialed it so ssh gets its own context, cancelled when it is closed
+		commandCtx, cancel := context.WithCancel(context.Background())
+
+		command := exec.CommandContext(commandCtx, "ssh", sshArgs...)
+
+		stdin, err := command.StdinPipe()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		stdout, err := command.StdoutPipe()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		stderr := new(strings.Builder)
+		command.Stderr = stderr
+
+		err = command.Start()
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("unable to run ssh: %s", err)
+		}
+
+		return &commandConn{
+			command: command,
+			cancel:  cancel,
+			stdin:   stdin,
+			stdout:  stdout,
+			stderr:  stderr,
+		}, nil
+	}
+}
+
+// commandConn Is a connection to the standard input and output of a command
+type commandConn struct {
+	command *exec.Cmd
+	cancel  context.CancelFunc
+	stdin   io.WriteCloser
+	stdout  io.ReadCloser
+	stderr  *strings.Builder
+}
+
+func (c *commandConn) Read(p []byte) (int, error) {
+
+	n, err := c.stdout.Read(p)
+
+	// Show why ssh failed instead of a bare EOF
+	if err == io.EOF && c.stderr.Len() > 0 {
+		return n, fmt.Errorf("ssh: %s", strings.TrimSpace(c.stderr.String()))
+	}
+
+	return n, err
+}
+
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+func (c *commandConn) Close() error {
+
+	c.stdin.Close()
+	c.stdout.Close()
+
+	// Cancelling the context kills ssh if it is still running
+	c.cancel()
+	c.command.Wait()
+
+	return nil
+}
+
+func (c *commandConn) LocalAddr() net.Addr {
+	return commandAddr{}
+}
+
+func (c *commandConn) RemoteAddr() net.Addr {
+	return commandAddr{}
+}
+
+// Deadlines aren't supported by pipes so requests rely on their context instead
+func (c *commandConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *commandConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *commandConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+type commandAddr struct{}
+
+func (commandAddr) Network() string {
+	return "command"
+}
+
+func (commandAddr) String() string {
+	return "command"
+}
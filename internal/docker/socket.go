@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// defaultSocket Is the socket Docker listens on by default, and where it is found inside the VMs of Docker Desktop alternatives
+const defaultSocket = "/var/run/docker.sock"
+
+// configuredHost Is the host set with SetHost, which is used instead of DOCKER_HOST or a detected socket
+var configuredHost string
+
+// resolvedHost Is the host the first client connected to so later clients don't have to search for it again
+var resolvedHost string
+
+// SetHost Sets the Docker host, such as unix:///path/to/docker.sock or ssh://me@dev.example.com, that clients connect to
+// instead of the one in DOCKER_HOST or a detected socket. An empty host restores detection.
+func SetHost(host string) {
+	configuredHost = host
+	resolvedHost = ""
+}
+
+// GetHost Returns the Docker host clients connect to, or an empty string if no client has been created yet
+func GetHost() string {
+	return resolvedHost
+}
+
+// getSocketCandidates Returns the sockets of rootless Docker and Docker Desktop alternatives, such as Colima and
+// Rancher Desktop, in the order they are tried when the default socket can't be reached
+func getSocketCandidates() []string {
+
+	candidates := []string{}
+
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); len(runtimeDir) > 0 {
+		candidates = append(candidates, filepath.Join(runtimeDir, "docker.sock"))
+	}
+
+	return append(candidates, getVMSockets()...)
+}
+
+// getVMSockets Returns the sockets Docker Desktop and its alternatives forward from the VM the daemon runs in
+func getVMSockets() []string {
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return []string{}
+	}
+
+	return []string{
+		filepath.Join(homeDir, ".colima", "default", "docker.sock"),
+		filepath.Join(homeDir, ".colima", "docker.sock"),
+		filepath.Join(homeDir, ".docker", "run", "docker.sock"),
+		filepath.Join(homeDir, ".docker", "desktop", "docker.sock"),
+		filepath.Join(homeDir, ".rd", "docker.sock"),
+	}
+}
+
+// detectSocketClient Returns a client for the first socket in getSocketCandidates that responds, or the fallback
+// client if none of them do
+func detectSocketClient(fallback *client.Client) *client.Client {
+
+	for _, socket := range getSocketCandidates() {
+
+		fileInfo, err := os.Stat(socket)
+		if err != nil || fileInfo.Mode()&os.ModeSocket == 0 {
+			continue
+		}
+
+		socketClient, err := client.NewClientWithOpts(client.FromEnv, client.WithHost(fmt.Sprintf("unix://%s", socket)))
+		if err != nil {
+			continue
+		}
+
+		if isReachable(socketClient) {
+			fallback.Close()
+			return socketClient
+		}
+
+		socketClient.Close()
+	}
+
+	return fallback
+}
+
+// isReachable Returns true if the Docker daemon of the client responds
+func isReachable(dockerClient *client.Client) bool {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := dockerClient.Ping(ctx)
+
+	return err == nil
+}
+
+// GetSocketPath Returns the path of the Docker socket as seen by the daemon, for containers that need to talk to Docker.
+// A socket on the host, such as one set in docker.host or DOCKER_HOST or that of rootless Docker, is where the client
+// found it. Docker Desktop and its alternatives run the daemon in a VM that has the socket at its default path instead
+// of the one forwarded to the host, as do remote hosts.
+func (d *DockerClient) GetSocketPath() string {
+
+	host := d.client.DaemonHost()
+
+	if !strings.HasPrefix(host, "unix://") {
+		return defaultSocket
+	}
+
+	socket := strings.TrimPrefix(host, "unix://")
+
+	for _, vmSocket := range getVMSockets() {
+		if socket == vmSocket {
+			return defaultSocket
+		}
+	}
+
+	return socket
+}
@@ -0,0 +1,40 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/client"
+)
+
+func TestGetSocketPath(t *testing.T) {
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"unix:///var/run/docker.sock":    defaultSocket,
+		"unix:///srv/docker/docker.sock": "/srv/docker/docker.sock",
+		"tcp://127.0.0.1:2375":           defaultSocket,
+		"unix://" + filepath.Join(homeDir, ".colima", "default", "docker.sock"): defaultSocket,
+	}
+
+	for host, expected := range tests {
+
+		dockerClient, err := client.NewClientWithOpts(client.WithHost(host))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d := &DockerClient{client: dockerClient}
+
+		if socket := d.GetSocketPath(); socket != expected {
+			t.Errorf("Expected %q for %s; received %q\n", expected, host, socket)
+		}
+
+		dockerClient.Close()
+	}
+}
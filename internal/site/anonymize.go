@@ -0,0 +1,212 @@
+package site
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+var validAnonymizeField = regexp.MustCompile(`^[A-Za-z0-9_]+\.[A-Za-z0-9_]+$`)
+
+var defaultAnonymizeFields = []string{
+	"users.user_email",
+	"users.user_login",
+	"users.user_nicename",
+	"users.display_name",
+	"users.user_url",
+	"comments.comment_author",
+	"comments.comment_author_email",
+	"comments.comment_author_IP",
+	"comments.comment_author_url",
+}
+
+// User meta is stored as rows so it can't be listed as a column in the "anonymizeFields" option
+var anonymizeUserMeta = []string{
+	"first_name",
+	"last_name",
+	"nickname",
+	"description",
+}
+
+// anonymizeDatabase Is the throwaway copy of the site's database that is anonymized for an export
+const anonymizeDatabase = databaseName + "_anonymize"
+
+// ExportAnonymizedDatabase Exports the site's database like ExportDatabase with the columns in the "anonymizeFields"
+// option, and the names in the user meta, replaced with fake data. The data is copied to a throwaway database that is
+// anonymized and exported so the site's own database is never changed.
+func (s *Site) ExportAnonymizedDatabase(file string, tables, excludeTables []string) error {
+
+	statements, err := s.getAnonymizeStatements()
+	if err != nil {
+		return err
+	}
+
+	databaseSize, err := s.getDatabaseSize()
+	if err != nil {
+		return err
+	}
+
+	// The copy takes as much space as the database itself
+	err = checkDiskSpace(path.Join(s.StaticConfig.SiteDirectory, "database"), databaseSize)
+	if err != nil {
+		return err
+	}
+
+	console.Info("Copying the database to anonymize it...")
+
+	// The copy is dropped even if the export fails. One left behind by an interrupted export is replaced by the next
+	defer s.runRootSQL(fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", anonymizeDatabase))
+
+	err = s.copyDatabase(anonymizeDatabase)
+	if err != nil {
+		return fmt.Errorf("unable to copy the database to anonymize it: %s", err)
+	}
+
+	console.Info("Anonymizing the copy of the database...")
+
+	err = s.runRootSQL(fmt.Sprintf("USE `%s`;\n%s", anonymizeDatabase, strings.Join(statements, ";\n")))
+	if err != nil {
+		return fmt.Errorf("unable to anonymize the copy of the database: %s", err)
+	}
+
+	return s.exportDatabase(file, tables, excludeTables, s.dumpAnonymizedDatabase)
+}
+
+// copyDatabase Copies every table of the site's database to the given database, replacing it if it exists
+func (s *Site) copyDatabase(target string) error {
+
+	results, err := s.QueryDatabase(baseTableNamesQuery)
+	if err != nil {
+		return err
+	}
+
+	statements := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", target),
+		fmt.Sprintf("CREATE DATABASE `%s`", target),
+	}
+
+	for _, row := range results[0].Rows {
+
+		table := strings.ReplaceAll(row[0], "`", "``")
+
+		statements = append(statements,
+			fmt.Sprintf("CREATE TABLE `%s`.`%s` LIKE `%s`.`%s`", target, table, databaseName, table),
+			fmt.Sprintf("INSERT INTO `%s`.`%s` SELECT * FROM `%s`.`%s`", target, table, databaseName, table))
+	}
+
+	return s.runRootSQL(strings.Join(statements, ";\n"))
+}
+
+// runRootSQL Runs the SQL as the database's root user, which can create and drop databases other than the site's own
+func (s *Site) runRootSQL(sql string) error {
+
+	container := s.getContainerName("database")
+
+	if _, isRunning := s.dockerClient.IsContainerRunning(container); !isRunning {
+		return fmt.Errorf("the site's database isn't running. Please run 'kana start' to start the site")
+	}
+
+	output, err := s.dockerClient.ContainerExec(container, "", []string{
+		fmt.Sprintf("mariadb -uroot -p%s -e '%s'", databaseRootPassword, strings.ReplaceAll(sql, "'", `'\''`)),
+	})
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("%s", strings.TrimSpace(output.StdErr))
+	}
+
+	return nil
+}
+
+// dumpAnonymizedDatabase Dumps the anonymized copy of the site's database with the mysqldump in the wp-cli image,
+// as wp-cli can only export the database the site uses
+func (s *Site) dumpAnonymizedDatabase(exportFile string, exportMounts []mount.Mount, tables, excludeTables []string) (int64, string, error) {
+
+	container, err := s.getWPCliContainer([]string{}, exportMounts)
+	if err != nil {
+		return 1, "", err
+	}
+
+	container.Command = []string{
+		"mysqldump",
+		fmt.Sprintf("--host=%s", s.getContainerName("database")),
+		"--user=root",
+		fmt.Sprintf("--password=%s", databaseRootPassword),
+		"--single-transaction",
+		fmt.Sprintf("--result-file=%s", exportFile),
+	}
+
+	for _, table := range excludeTables {
+		container.Command = append(container.Command, fmt.Sprintf("--ignore-table=%s.%s", anonymizeDatabase, table))
+	}
+
+	container.Command = append(append(container.Command, anonymizeDatabase), tables...)
+
+	return s.dockerClient.ContainerRunAndClean(container)
+}
+
+// getAnonymizeStatements Returns the SQL statements that replace the personal data in the site's database with fake data
+func (s *Site) getAnonymizeStatements() ([]string, error) {
+
+	tablePrefix := s.Settings.TablePrefix
+	fields := s.Settings.AnonymizeFields
+	tables := []string{fmt.Sprintf("%susermeta", tablePrefix)}
+	statements := []string{}
+
+	for _, field := range fields {
+
+		if !validAnonymizeField.MatchString(field) {
+			return statements, fmt.Errorf("invalid field %q in anonymizeFields. Please use the form \"table.column\" with the table name without its prefix, such as \"users.user_email\"", field)
+		}
+
+		table, column, _ := strings.Cut(field, ".")
+		table = tablePrefix + table
+
+		if !appConfig.CheckString(table, tables) {
+			tables = append(tables, table)
+		}
+
+		statements = append(statements, fmt.Sprintf(
+			"UPDATE `%s` SET `%s` = %s WHERE `%s` IS NOT NULL AND `%s` != ''",
+			table, column, getFakeValue(column), column, column))
+	}
+
+	// Check every table exists first so a typo doesn't leave the database half anonymized
+	err := s.validateTables(tables)
+	if err != nil {
+		return statements, err
+	}
+
+	statements = append(statements, fmt.Sprintf(
+		"UPDATE `%susermeta` SET `meta_value` = %s WHERE `meta_key` IN ('%s') AND `meta_value` != ''",
+		tablePrefix, getFakeValue("meta_value"), strings.Join(anonymizeUserMeta, "', '")))
+
+	return statements, nil
+}
+
+// getFakeValue Returns the SQL expression that replaces the value of the column with fake data. Values are replaced
+// with part of their hash so different values stay different and the same value is replaced the same way everywhere.
+func getFakeValue(column string) string {
+
+	lowerColumn := strings.ToLower(column)
+	hash := fmt.Sprintf("LEFT(MD5(`%s`), 12)", column)
+
+	switch {
+	case strings.Contains(lowerColumn, "email"):
+		return fmt.Sprintf("CONCAT(%s, '@example.com')", hash)
+	case strings.HasSuffix(lowerColumn, "_ip"):
+		return "'127.0.0.1'"
+	case strings.HasSuffix(lowerColumn, "url"):
+		return "''"
+	default:
+		return fmt.Sprintf("CONCAT('anonymous_', %s)", hash)
+	}
+}
@@ -0,0 +1,146 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+type ApplicationPassword struct {
+	UUID     string      `json:"uuid"`
+	AppID    string      `json:"app_id"`
+	Name     string      `json:"name"`
+	User     string      `json:"user"`
+	Password string      `json:"password,omitempty"`
+	Created  json.Number `json:"created"`
+	LastUsed json.Number `json:"last_used"`
+	LastIP   string      `json:"last_ip"`
+}
+
+// DestroySessions Logs the given WordPress user out everywhere by destroying all of their sessions
+func (s *Site) DestroySessions(user string) error {
+
+	err := s.checkUser(user)
+	if err != nil {
+		return err
+	}
+
+	return s.runAuthCommand([]string{"user", "session", "destroy", user, "--all"})
+}
+
+// CreateApplicationPassword Creates an application password for the given user to authenticate REST API requests with,
+// returning it along with the UUID needed to delete it again. The password can't be read back once this returns.
+func (s *Site) CreateApplicationPassword(user, name string) (ApplicationPassword, error) {
+
+	applicationPassword := ApplicationPassword{}
+
+	err := s.checkUser(user)
+	if err != nil {
+		return applicationPassword, err
+	}
+
+	statusCode, output, err := s.runWPCli([]string{"user", "application-password", "create", user, name, "--porcelain"}, []mount.Mount{})
+	if err != nil {
+		return applicationPassword, err
+	}
+
+	if statusCode != 0 {
+		return applicationPassword, fmt.Errorf("unable to create the application password: %s", strings.TrimSpace(output))
+	}
+
+	password := strings.TrimSpace(output)
+
+	applicationPasswords, err := s.GetApplicationPasswords(user)
+	if err != nil {
+		return applicationPassword, err
+	}
+
+	// Names don't have to be unique so the newest password with the name is the one just created
+	var newest int64 = -1
+
+	for _, existingPassword := range applicationPasswords {
+
+		created, _ := existingPassword.Created.Int64()
+
+		if existingPassword.Name == name && created >= newest {
+			applicationPassword = existingPassword
+			newest = created
+		}
+	}
+
+	applicationPassword.User = user
+	applicationPassword.Name = name
+	applicationPassword.Password = password
+
+	return applicationPassword, nil
+}
+
+// GetApplicationPasswords Returns the application passwords of the given user without the passwords themselves
+func (s *Site) GetApplicationPasswords(user string) ([]ApplicationPassword, error) {
+
+	applicationPasswords := []ApplicationPassword{}
+
+	statusCode, output, err := s.runWPCli([]string{
+		"user",
+		"application-password",
+		"list",
+		user,
+		"--fields=uuid,app_id,name,created,last_used,last_ip",
+		"--format=json",
+	}, []mount.Mount{})
+	if err != nil {
+		return applicationPasswords, err
+	}
+
+	if statusCode != 0 {
+		return applicationPasswords, fmt.Errorf("unable to list the application passwords of %s: %s", user, strings.TrimSpace(output))
+	}
+
+	err = json.Unmarshal([]byte(output), &applicationPasswords)
+	if err != nil {
+		return applicationPasswords, err
+	}
+
+	for i := range applicationPasswords {
+		applicationPasswords[i].User = user
+	}
+
+	return applicationPasswords, nil
+}
+
+// DeleteApplicationPassword Deletes the application password with the given UUID, or all of the user's application
+// passwords if the UUID is empty
+func (s *Site) DeleteApplicationPassword(user, uuid string) error {
+
+	err := s.checkUser(user)
+	if err != nil {
+		return err
+	}
+
+	command := []string{"user", "application-password", "delete", user}
+
+	if len(uuid) > 0 {
+		command = append(command, uuid)
+	} else {
+		command = append(command, "--all")
+	}
+
+	return s.runAuthCommand(command)
+}
+
+// runAuthCommand Runs a wp-cli command that changes a user's credentials, returning its output as an error if it fails
+func (s *Site) runAuthCommand(command []string) error {
+
+	statusCode, output, err := s.runWPCli(command, []mount.Mount{})
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 0 {
+		return fmt.Errorf("%s", strings.TrimSpace(output))
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const backupTimestampFormat = "20060102150405"
+
+// BackupDatabase Exports the site's database to a timestamped file in the site's backup directory.
+// When keep is greater than zero only the newest keep backups are kept. Returns the timestamp of the new backup.
+func (s *Site) BackupDatabase(keep int) (string, error) {
+
+	backupDirectory := s.getBackupDirectory()
+
+	err := os.MkdirAll(backupDirectory, 0750)
+	if err != nil {
+		return "", err
+	}
+
+	timestamp := time.Now().Format(backupTimestampFormat)
+
+	err = s.ExportDatabase(path.Join(backupDirectory, fmt.Sprintf("%s.sql", timestamp)), nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if keep > 0 {
+		err = s.pruneBackups(keep)
+	}
+
+	return timestamp, err
+}
+
+// ArchiveDatabase Exports the site's database to the app's archive folder, which is kept when the site is destroyed,
+// starting the site's containers first if needed. Returns the path to the exported file.
+func (s *Site) ArchiveDatabase() (string, error) {
+
+	if _, err := os.Stat(path.Join(s.StaticConfig.SiteDirectory, "database")); os.IsNotExist(err) {
+		return "", fmt.Errorf("the site doesn't have a database to back up")
+	}
+
+	if !s.IsSiteRunning() {
+
+		err := s.StartWordPress()
+		if err != nil {
+			return "", err
+		}
+
+		err = s.waitForDatabase()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	archiveDirectory := path.Join(s.StaticConfig.AppDirectory, "backups", s.StaticConfig.SiteName)
+
+	err := os.MkdirAll(archiveDirectory, 0750)
+	if err != nil {
+		return "", err
+	}
+
+	archiveFile := path.Join(archiveDirectory, fmt.Sprintf("%s.sql", time.Now().Format(backupTimestampFormat)))
+
+	return archiveFile, s.ExportDatabase(archiveFile, nil, nil)
+}
+
+// GetBackups Returns the timestamps of the site's database backups, oldest first
+func (s *Site) GetBackups() ([]string, error) {
+
+	backups := []string{}
+
+	files, err := os.ReadDir(s.getBackupDirectory())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return backups, nil
+		}
+
+		return backups, err
+	}
+
+	for _, file := range files {
+
+		timestamp := strings.TrimSuffix(file.Name(), ".sql")
+
+		if file.IsDir() || timestamp == file.Name() {
+			continue
+		}
+
+		_, err = time.Parse(backupTimestampFormat, timestamp)
+		if err == nil {
+			backups = append(backups, timestamp)
+		}
+	}
+
+	sort.Strings(backups)
+
+	return backups, nil
+}
+
+// RestoreBackup Imports the database backup with the given timestamp, replacing the site's current database
+func (s *Site) RestoreBackup(timestamp string) error {
+
+	backupFile := path.Join(s.getBackupDirectory(), fmt.Sprintf("%s.sql", timestamp))
+
+	_, err := os.Stat(backupFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no backup found for %s. Run 'kana backup list' to see the available backups", timestamp)
+		}
+
+		return err
+	}
+
+	return s.ImportDatabase(backupFile)
+}
+
+// getBackupDirectory Returns the directory the site's database backups are saved in
+func (s *Site) getBackupDirectory() string {
+	return path.Join(s.StaticConfig.SiteDirectory, "backups")
+}
+
+// pruneBackups Removes all but the newest keep database backups
+func (s *Site) pruneBackups(keep int) error {
+
+	backups, err := s.GetBackups()
+	if err != nil {
+		return err
+	}
+
+	if len(backups) <= keep {
+		return nil
+	}
+
+	for _, timestamp := range backups[:len(backups)-keep] {
+		err = os.Remove(path.Join(s.getBackupDirectory(), fmt.Sprintf("%s.sql", timestamp)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
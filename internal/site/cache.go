@@ -0,0 +1,32 @@
+package site
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// FlushCache Flushes the site's object cache, including Redis or Memcached when a drop-in is using them, and returns the type of cache that was flushed
+func (s *Site) FlushCache() (string, error) {
+
+	statusCode, output, err := s.runWPCli([]string{"cache", "flush"}, []mount.Mount{})
+	if err != nil {
+		return "", err
+	}
+
+	if statusCode != 0 {
+		return "", fmt.Errorf("unable to flush the cache: %s", strings.TrimSpace(output))
+	}
+
+	statusCode, cacheType, err := s.runWPCli([]string{"cache", "type"}, []mount.Mount{})
+	if err != nil {
+		return "", err
+	}
+
+	if statusCode != 0 {
+		return "", fmt.Errorf("unable to get the cache type: %s", strings.TrimSpace(cacheType))
+	}
+
+	return strings.TrimSpace(cacheType), nil
+}
@@ -0,0 +1,125 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// wp-cli reports each core file that fails as a warning such as "Warning: File doesn't verify against checksum: wp-login.php"
+var checksumWarning = regexp.MustCompile(`(?m)^Warning: (.+?): (.+?)\s*$`)
+
+// wp-cli skips plugins it can't find the version or checksums of, such as "Warning: Could not retrieve the checksums
+// for version 1.0.0 of plugin my-plugin, skipping."
+var unverifiedPluginWarning = regexp.MustCompile(`(?m)^Warning: Could not retrieve the (?:checksums for version \S+ of plugin|version for plugin) ([^\s,]+), skipping\.`)
+
+type ModifiedFile struct {
+	Source  string `json:"source"`
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// VerifyChecksums Checks the site's WordPress core files and plugins against the checksums from WordPress.org,
+// returning every file that was changed, added or is missing and the plugins that couldn't be verified as they
+// aren't from WordPress.org. Plugins mounted from the site's own folder are skipped.
+func (s *Site) VerifyChecksums() ([]ModifiedFile, []string, error) {
+
+	coreFiles, err := s.verifyCoreChecksums()
+	if err != nil {
+		return coreFiles, []string{}, err
+	}
+
+	pluginFiles, unverifiedPlugins, err := s.verifyPluginChecksums()
+
+	return append(coreFiles, pluginFiles...), unverifiedPlugins, err
+}
+
+// verifyCoreChecksums Returns the WordPress core files that don't match their checksums
+func (s *Site) verifyCoreChecksums() ([]ModifiedFile, error) {
+
+	modifiedFiles := []ModifiedFile{}
+
+	statusCode, output, err := s.runWPCli([]string{"core", "verify-checksums"}, []mount.Mount{})
+	if err != nil || statusCode == 0 {
+		return modifiedFiles, err
+	}
+
+	for _, match := range checksumWarning.FindAllStringSubmatch(output, -1) {
+		modifiedFiles = append(modifiedFiles, ModifiedFile{
+			Source:  "core",
+			File:    match[2],
+			Message: match[1],
+		})
+	}
+
+	if len(modifiedFiles) == 0 {
+		return modifiedFiles, fmt.Errorf("unable to verify the WordPress core files: %s", strings.TrimSpace(output))
+	}
+
+	return modifiedFiles, nil
+}
+
+// verifyPluginChecksums Returns the plugin files that don't match their checksums and the plugins that can't be
+// verified as WordPress.org doesn't have checksums for them
+func (s *Site) verifyPluginChecksums() ([]ModifiedFile, []string, error) {
+
+	modifiedFiles := []ModifiedFile{}
+	unverifiedPlugins := []string{}
+
+	verifyCommand := []string{"plugin", "verify-checksums", "--all", "--format=json"}
+
+	if mountedPlugins := s.getMountedExtensions("plugin"); len(mountedPlugins) > 0 {
+		verifyCommand = append(verifyCommand, fmt.Sprintf("--exclude=%s", strings.Join(mountedPlugins, ",")))
+	}
+
+	statusCode, output, err := s.runWPCli(verifyCommand, []mount.Mount{})
+	if err != nil {
+		return modifiedFiles, unverifiedPlugins, err
+	}
+
+	// Plugins that aren't from WordPress.org are skipped with a warning rather than failing
+	for _, match := range unverifiedPluginWarning.FindAllStringSubmatch(output, -1) {
+		unverifiedPlugins = append(unverifiedPlugins, match[1])
+	}
+
+	if statusCode == 0 {
+		return modifiedFiles, unverifiedPlugins, nil
+	}
+
+	// The JSON is followed by an error line counting the plugins that didn't verify
+	for _, line := range strings.Split(output, "\n") {
+
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		pluginFiles := []struct {
+			PluginName string `json:"plugin_name"`
+			File       string `json:"file"`
+			Message    string `json:"message"`
+		}{}
+
+		err = json.Unmarshal([]byte(line), &pluginFiles)
+		if err != nil {
+			return modifiedFiles, unverifiedPlugins, err
+		}
+
+		for _, pluginFile := range pluginFiles {
+			modifiedFiles = append(modifiedFiles, ModifiedFile{
+				Source:  fmt.Sprintf("plugin %s", pluginFile.PluginName),
+				File:    pluginFile.File,
+				Message: pluginFile.Message,
+			})
+		}
+	}
+
+	if len(modifiedFiles) == 0 {
+		return modifiedFiles, unverifiedPlugins, fmt.Errorf("unable to verify the plugins: %s", strings.TrimSpace(output))
+	}
+
+	return modifiedFiles, unverifiedPlugins, nil
+}
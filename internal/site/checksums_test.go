@@ -0,0 +1,24 @@
+package site
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnverifiedPluginWarning(t *testing.T) {
+
+	output := `Warning: Could not retrieve the checksums for version 1.2.0 of plugin my-plugin, skipping.
+Warning: Could not retrieve the version for plugin custom_plugin, skipping.
+Warning: File doesn't verify against checksum: wp-login.php
+Success: Verified 1 of 3 plugins (2 skipped).`
+
+	received := []string{}
+
+	for _, match := range unverifiedPluginWarning.FindAllStringSubmatch(output, -1) {
+		received = append(received, match[1])
+	}
+
+	if strings.Join(received, ",") != "my-plugin,custom_plugin" {
+		t.Errorf("Expected my-plugin and custom_plugin to be unverified; received %v", received)
+	}
+}
@@ -0,0 +1,59 @@
+package site
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Companions Are the web UIs Kana runs alongside sites, keyed by the name used with 'kana open --service'
+var Companions = map[string]string{
+	"traefik": "the Traefik dashboard",
+}
+
+// GetCompanionNames Returns the names of the companions in alphabetical order
+func GetCompanionNames() []string {
+
+	names := []string{}
+
+	for name := range Companions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// GetCompanionURL Returns the URL of the given companion's web UI or an error if the companion isn't enabled.
+// The Traefik dashboard is shared by all sites.
+func (s *Site) GetCompanionURL(companion string) (string, error) {
+
+	title, ok := Companions[companion]
+	if !ok {
+		return "", fmt.Errorf("invalid service %q. Please choose one of %s", companion, strings.Join(GetCompanionNames(), ", "))
+	}
+
+	if !s.DynamicConfig.GetBool("traefik.dashboard") {
+		return "", fmt.Errorf("%s isn't enabled. Run 'kana config traefik.dashboard true' and 'kana proxy restart' to enable it", title)
+	}
+
+	return fmt.Sprintf("https://traefik.%s/", s.StaticConfig.AppDomain), nil
+}
+
+// OpenCompanion Opens the given companion's web UI in a browser if it is enabled and responding
+func (s *Site) OpenCompanion(companion string) error {
+
+	companionURL, err := s.GetCompanionURL(companion)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.verifyURL(companionURL, http.StatusOK, false)
+	if err != nil {
+		return err
+	}
+
+	return openURL(companionURL)
+}
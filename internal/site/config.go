@@ -2,21 +2,36 @@ package site
 
 import (
 	"fmt"
+	"math"
+	"net/http"
 	"os"
 	"path"
 	"strings"
 
 	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 type SiteFlags struct {
-	Xdebug   bool
-	Local    bool
-	IsTheme  bool
-	IsPlugin bool
+	Xdebug      bool
+	Local       bool
+	IsTheme     bool
+	IsPlugin    bool
+	KeepConfig  bool
+	Insecure    bool
+	Gitignore   bool
+	SkipPlugins bool
+}
+
+type SiteUser struct {
+	Username string `mapstructure:"username"`
+	Email    string `mapstructure:"email"`
+	Role     string `mapstructure:"role"`
+	Password string `mapstructure:"password"`
 }
 
 // getSiteConfig Get the config items that can be overridden locally with a .kana.json file.
@@ -24,11 +39,7 @@ func getSiteConfig(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Vip
 
 	siteConfig := viper.New()
 
-	siteConfig.SetDefault("php", dynamicConfig.GetString("php"))
-	siteConfig.SetDefault("type", dynamicConfig.GetString("type"))
-	siteConfig.SetDefault("local", dynamicConfig.GetBool("local"))
-	siteConfig.SetDefault("xdebug", dynamicConfig.GetBool("xdebug"))
-	siteConfig.SetDefault("plugins", []string{})
+	setSiteConfigDefaults(siteConfig, dynamicConfig)
 
 	siteConfig.SetConfigName(".kana")
 	siteConfig.SetConfigType("json")
@@ -42,9 +53,114 @@ func getSiteConfig(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Vip
 		}
 	}
 
+	// Fall back to a valid PHP version so a missing or bad value can't produce a broken image tag
+	if !appConfig.CheckString(siteConfig.GetString("php"), appConfig.ValidPHPVersions) {
+
+		phpVersion := dynamicConfig.GetString("php")
+		if !appConfig.CheckString(phpVersion, appConfig.ValidPHPVersions) {
+			phpVersion = appConfig.DefaultPHPVersion
+		}
+
+		console.Warn("Invalid PHP version %q in .kana.json. Defaulting to PHP %s.", siteConfig.GetString("php"), phpVersion)
+		siteConfig.Set("php", phpVersion)
+	}
+
+	subdirectory := strings.Trim(siteConfig.GetString("subdirectory"), "/")
+	if len(subdirectory) > 0 && !validSubdirectory.MatchString(subdirectory) {
+		console.Warn("Invalid subdirectory %q in .kana.json. Installing WordPress at the root of the site.", siteConfig.GetString("subdirectory"))
+		subdirectory = ""
+	}
+
+	siteConfig.Set("subdirectory", subdirectory)
+
+	// A kept wp-config.php doesn't read the URL constants Kana passes to the container
+	if len(subdirectory) > 0 && siteConfig.GetBool("keepConfig") {
+		console.Warn("The subdirectory option sets WP_HOME and WP_SITEURL in the container's wp-config.php. As keepConfig is set, make sure your wp-config.php sets them to the URL of %s.", subdirectory)
+	}
+
+	if len(siteConfig.GetString("timezone")) > 0 && !isValidTimezone(siteConfig.GetString("timezone")) {
+		console.Warn("Invalid timezone %q in .kana.json. Please use a timezone such as \"Europe/Berlin\" or an offset such as \"UTC-5\". Keeping the site's current timezone.", siteConfig.GetString("timezone"))
+		siteConfig.Set("timezone", "")
+	}
+
+	if !validTablePrefix.MatchString(siteConfig.GetString("tablePrefix")) {
+		console.Warn("Invalid table prefix %q in .kana.json. Please use only letters, numbers and underscores. Defaulting to wp_.", siteConfig.GetString("tablePrefix"))
+		siteConfig.Set("tablePrefix", "wp_")
+	}
+
+	// Reserved labels are dropped when the settings are loaded, which happens again each time a setting changes
+	if labels, ok := readRawLabels(siteConfig.ConfigFileUsed()); ok {
+		for label := range labels {
+			if isReservedLabel(label, dynamicConfig) {
+				console.Warn("The label %q in .kana.json is reserved for Kana and will be ignored.", label)
+			}
+		}
+	}
+
+	if siteConfig.GetInt("traefik.priority") < 0 {
+		console.Warn("Invalid Traefik priority %d in .kana.json. Using Traefik's default priority.", siteConfig.GetInt("traefik.priority"))
+		siteConfig.Set("traefik.priority", 0)
+	}
+
 	return siteConfig, nil
 }
 
+// setSiteConfigDefaults Sets the default of every option a site can override in its .kana.json file
+func setSiteConfigDefaults(siteConfig *viper.Viper, dynamicConfig *viper.Viper) {
+
+	siteConfig.SetDefault("php", dynamicConfig.GetString("php"))
+	siteConfig.SetDefault("phpVersions", []string{})
+	siteConfig.SetDefault("phpExtensions", []string{})
+	siteConfig.SetDefault("type", dynamicConfig.GetString("type"))
+	siteConfig.SetDefault("local", dynamicConfig.GetBool("local"))
+	siteConfig.SetDefault("xdebug", dynamicConfig.GetBool("xdebug"))
+	siteConfig.SetDefault("xdebugMode", "debug")
+	siteConfig.SetDefault("xdebugOutputDir", "")
+	siteConfig.SetDefault("insecure", dynamicConfig.GetBool("insecure"))
+	siteConfig.SetDefault("keepConfig", false)
+	siteConfig.SetDefault("skipPlugins", false)
+	siteConfig.SetDefault("gitignore", dynamicConfig.GetBool("gitignore"))
+	siteConfig.SetDefault("hosts", dynamicConfig.GetBool("hosts"))
+	siteConfig.SetDefault("hostUser", dynamicConfig.GetBool("hostUser"))
+	siteConfig.SetDefault("restartMode", dynamicConfig.GetString("restartMode"))
+	siteConfig.SetDefault("wordpressVersion", "latest")
+	siteConfig.SetDefault("tablePrefix", "wp_")
+	siteConfig.SetDefault("anonymizeFields", defaultAnonymizeFields)
+	siteConfig.SetDefault("aliases.database", []string{})
+	siteConfig.SetDefault("aliases.wordpress", []string{})
+	siteConfig.SetDefault("labels", map[string]string{})
+	siteConfig.SetDefault("command", []string{})
+	siteConfig.SetDefault("traefik.priority", 0)
+	siteConfig.SetDefault("traefik.middlewares", []string{})
+	siteConfig.SetDefault("traefik.basicAuth", []string{})
+	siteConfig.SetDefault("plugins", []string{})
+	siteConfig.SetDefault("themes", []string{})
+	siteConfig.SetDefault("activeTheme", "")
+	siteConfig.SetDefault("languages", []string{})
+	siteConfig.SetDefault("language", "")
+	siteConfig.SetDefault("users", []SiteUser{})
+	siteConfig.SetDefault("database.seed", []string{})
+	siteConfig.SetDefault("database.seedAlways", false)
+	siteConfig.SetDefault("database.expose", false)
+	siteConfig.SetDefault("database.port", 0)
+	siteConfig.SetDefault("database.ephemeral", false)
+	siteConfig.SetDefault("subdirectory", "")
+	siteConfig.SetDefault("directories", []string{})
+	siteConfig.SetDefault("muPlugins", "")
+	siteConfig.SetDefault("demoContent", false)
+	siteConfig.SetDefault("demoContentFile", "")
+	siteConfig.SetDefault("uploads.directory", "")
+	siteConfig.SetDefault("uploads.readOnly", false)
+	siteConfig.SetDefault("verifyRestAPI", true)
+	siteConfig.SetDefault("healthPath", "")
+	siteConfig.SetDefault("healthStatus", http.StatusOK)
+	siteConfig.SetDefault("dockerfile", "")
+	siteConfig.SetDefault("entrypoint", "")
+	siteConfig.SetDefault("timezone", "")
+	siteConfig.SetDefault("dateFormat", "")
+	siteConfig.SetDefault("timeFormat", "")
+}
+
 func (s *Site) ExportSiteConfig() error {
 
 	config := s.GetRunningConfig()
@@ -53,18 +169,59 @@ func (s *Site) ExportSiteConfig() error {
 		return err
 	}
 
-	s.SiteConfig.Set("local", config.Local)
-	s.SiteConfig.Set("type", config.Type)
-	s.SiteConfig.Set("xdebug", config.Xdebug)
-	s.SiteConfig.Set("plugins", plugins)
+	s.setSetting("local", config.Local)
+	s.setSetting("type", config.Type)
+	s.setSetting("xdebug", config.Xdebug)
+	s.setSetting("insecure", config.Insecure)
+	s.setSetting("plugins", plugins)
+
+	return s.writeSiteConfig()
+}
+
+// writeSiteConfig Saves the current site config to the .kana.json file, creating it if needed
+func (s *Site) writeSiteConfig() error {
 
-	if _, err = os.Stat(path.Join(s.StaticConfig.WorkingDirectory, ".kana.json")); os.IsNotExist(err) {
+	if _, err := os.Stat(path.Join(s.StaticConfig.WorkingDirectory, ".kana.json")); os.IsNotExist(err) {
 		return s.SiteConfig.SafeWriteConfig()
 	}
 
 	return s.SiteConfig.WriteConfig()
 }
 
+// getSiteUsers Returns the additional users to create on the site, validating each of them
+func (s *Site) getSiteUsers() ([]SiteUser, error) {
+
+	// Copy the users so filling in their defaults doesn't change the settings
+	users := append([]SiteUser{}, s.Settings.Users...)
+
+	validate := validator.New()
+
+	for i, user := range users {
+
+		if len(user.Username) == 0 {
+			return users, fmt.Errorf("invalid user. Each user must have a username")
+		}
+
+		if len(user.Role) == 0 {
+			users[i].Role = "subscriber"
+		} else if !appConfig.CheckString(user.Role, appConfig.ValidRoles) {
+			return users, fmt.Errorf("invalid role %q for user %s. Please choose one of: %s", user.Role, user.Username, strings.Join(appConfig.ValidRoles, ", "))
+		}
+
+		if len(user.Email) == 0 {
+			users[i].Email = fmt.Sprintf("%s@%s", user.Username, s.siteDomain)
+		} else if err := validate.Var(user.Email, "email"); err != nil {
+			return users, fmt.Errorf("invalid email %q for user %s", user.Email, user.Username)
+		}
+
+		if len(user.Password) == 0 {
+			users[i].Password = s.DynamicConfig.GetString("admin.password")
+		}
+	}
+
+	return users, nil
+}
+
 // IsLocalSite Determines if a site is a "local" site (started with the "local" flag) so that other commands can work as needed.
 func (s *Site) IsLocalSite() bool {
 
@@ -93,26 +250,42 @@ func (s *Site) IsLocalSite() bool {
 	}
 
 	// Return the flag for all other conditions
-	return s.SiteConfig.GetBool("local")
+	return s.Settings.Local
 }
 
 // ProcessSiteFlags Process the start flags and save them to the settings object
 func (s *Site) ProcessSiteFlags(cmd *cobra.Command, flags SiteFlags) {
 
 	if cmd.Flags().Lookup("local").Changed {
-		s.SiteConfig.Set("local", flags.Local)
+		s.setSetting("local", flags.Local)
 	}
 
 	if cmd.Flags().Lookup("xdebug").Changed {
-		s.SiteConfig.Set("xdebug", flags.Xdebug)
+		s.setSetting("xdebug", flags.Xdebug)
+	}
+
+	if cmd.Flags().Lookup("insecure").Changed {
+		s.setSetting("insecure", flags.Insecure)
+	}
+
+	if cmd.Flags().Lookup("gitignore").Changed {
+		s.setSetting("gitignore", flags.Gitignore)
+	}
+
+	if cmd.Flags().Lookup("keep-config").Changed {
+		s.setSetting("keepConfig", flags.KeepConfig)
+	}
+
+	if cmd.Flags().Lookup("skip-plugins").Changed {
+		s.setSetting("skipPlugins", flags.SkipPlugins)
 	}
 
 	if cmd.Flags().Lookup("plugin").Changed && flags.IsPlugin {
-		s.SiteConfig.Set("type", "plugin")
+		s.setSetting("type", "plugin")
 	}
 
 	if cmd.Flags().Lookup("theme").Changed && flags.IsTheme {
-		s.SiteConfig.Set("type", "theme")
+		s.setSetting("type", "theme")
 	}
 }
 
@@ -120,9 +293,10 @@ func (s *Site) ProcessSiteFlags(cmd *cobra.Command, flags SiteFlags) {
 func (s *Site) GetRunningConfig() CurrentConfig {
 
 	currentConfig := CurrentConfig{
-		Type:   "site",
-		Local:  false,
-		Xdebug: false,
+		Type:          "site",
+		Local:         false,
+		Xdebug:        false,
+		PHPExtensions: []string{},
 	}
 
 	output, _ := s.runCli("pecl list | grep xdebug", false)
@@ -130,7 +304,24 @@ func (s *Site) GetRunningConfig() CurrentConfig {
 		currentConfig.Xdebug = true
 	}
 
-	mounts := s.dockerClient.ContainerGetMounts(fmt.Sprintf("kana_%s_wordpress", s.StaticConfig.SiteName))
+	// Only the extensions the site asks for are tracked as the image comes with many more
+	activeExtensions, _ := s.getActivePHPExtensions()
+
+	for _, extension := range s.Settings.PHPExtensions {
+		if activeExtension(extension, activeExtensions) {
+			currentConfig.PHPExtensions = append(currentConfig.PHPExtensions, extension)
+		}
+	}
+
+	wordPressContainer := s.getContainerName("wordpress")
+
+	// Sites in insecure mode don't have a TLS router
+	labels := s.dockerClient.ContainerGetLabels(wordPressContainer)
+	if _, hasTLSRouter := labels[fmt.Sprintf("traefik.http.routers.wordpress-%s.tls", s.StaticConfig.SiteName)]; len(labels) > 0 && !hasTLSRouter {
+		currentConfig.Insecure = true
+	}
+
+	mounts := s.dockerClient.ContainerGetMounts(wordPressContainer)
 
 	if len(mounts) == 1 {
 		currentConfig.Type = "site"
@@ -142,14 +333,229 @@ func (s *Site) GetRunningConfig() CurrentConfig {
 			currentConfig.Local = true
 		}
 
-		if strings.Contains(mount.Destination, "/var/www/html/wp-content/plugins/") {
+		// Extensions mounted from the "directories" option come from folders below the working directory
+		isProject := mount.Source == s.StaticConfig.WorkingDirectory
+
+		if strings.Contains(mount.Destination, "/wp-content/plugins/") {
 			currentConfig.Type = "plugin"
+
+			if !isProject {
+				currentConfig.Type = "plugins"
+			}
 		}
 
-		if strings.Contains(mount.Destination, "/var/www/html/wp-content/themes/") {
+		if strings.Contains(mount.Destination, "/wp-content/themes/") {
 			currentConfig.Type = "theme"
+
+			if !isProject {
+				currentConfig.Type = "themes"
+			}
 		}
 	}
 
 	return currentConfig
 }
+
+// ValidateConfig Checks the app config and the site's .kana.json file, returning every problem found in either
+func (s *Site) ValidateConfig() ([]appConfig.ConfigProblem, error) {
+
+	problems, err := appConfig.ValidateDynamicConfig(s.DynamicConfig)
+	if err != nil {
+		return problems, err
+	}
+
+	siteProblems, err := ValidateSiteConfigFile(s.GetSiteConfigFile())
+
+	return append(problems, siteProblems...), err
+}
+
+// GetSiteConfigFile Returns the path of the site's .kana.json file, which may not exist yet
+func (s *Site) GetSiteConfigFile() string {
+	return path.Join(s.StaticConfig.WorkingDirectory, ".kana.json")
+}
+
+// ValidateSiteConfigFile Checks the given file against the rules for a site's .kana.json file, returning every problem found
+func ValidateSiteConfigFile(file string) ([]appConfig.ConfigProblem, error) {
+
+	rules := map[string]appConfig.ConfigRule{
+		"activeTheme":         appConfig.StringRule(""),
+		"anonymizeFields":     anonymizeFieldsRule,
+		"aliases.database":    appConfig.StringListRule([]string{}),
+		"aliases.wordpress":   appConfig.StringListRule([]string{}),
+		"command":             appConfig.StringListRule([]string{}),
+		"database.ephemeral":  appConfig.BoolRule(),
+		"database.expose":     appConfig.BoolRule(),
+		"database.port":       appConfig.IntRule(0, 65535),
+		"database.seed":       appConfig.StringListRule([]string{}),
+		"database.seedAlways": appConfig.BoolRule(),
+		"dateFormat":          appConfig.StringRule(""),
+		"demoContent":         appConfig.BoolRule(),
+		"demoContentFile":     appConfig.StringRule(""),
+		"directories":         appConfig.StringListRule([]string{}),
+		"dockerfile":          appConfig.StringRule(""),
+		"entrypoint":          appConfig.StringRule(""),
+		"gitignore":           appConfig.BoolRule(),
+		"healthPath":          healthPathRule,
+		"healthStatus":        appConfig.IntRule(100, 599),
+		"hostUser":            appConfig.BoolRule(),
+		"hosts":               appConfig.BoolRule(),
+		"insecure":            appConfig.BoolRule(),
+		"keepConfig":          appConfig.BoolRule(),
+		"labels":              appConfig.StringMapRule(),
+		"language":            appConfig.StringRule(""),
+		"languages":           appConfig.StringListRule([]string{}),
+		"local":               appConfig.BoolRule(),
+		"muPlugins":           appConfig.StringRule(""),
+		"name":                appConfig.StringRule(""),
+		"php":                 appConfig.OneOfRule(appConfig.ValidPHPVersions),
+		"phpExtensions":       phpExtensionsRule,
+		"phpVersions":         appConfig.StringListRule(appConfig.ValidPHPVersions),
+		"plugins":             appConfig.StringListRule([]string{}),
+		"restartMode":         appConfig.OneOfRule(appConfig.ValidRestartModes),
+		"skipPlugins":         appConfig.BoolRule(),
+		"subdirectory":        subdirectoryRule,
+		"tablePrefix":         tablePrefixRule,
+		"themes":              appConfig.StringListRule([]string{}),
+		"timeFormat":          appConfig.StringRule(""),
+		"timezone":            timezoneRule,
+		"traefik.basicAuth":   appConfig.StringListRule([]string{}),
+		"traefik.middlewares": appConfig.StringListRule([]string{}),
+		"traefik.priority":    appConfig.IntRule(0, math.MaxInt32),
+		"type":                appConfig.OneOfRule(appConfig.ValidTypes),
+		"uploads.directory":   appConfig.StringRule(""),
+		"uploads.readOnly":    appConfig.BoolRule(),
+		"users":               usersRule,
+		"verifyRestAPI":       appConfig.BoolRule(),
+		"wordpressVersion":    appConfig.StringRule(""),
+		"xdebug":              appConfig.BoolRule(),
+		"xdebugMode":          appConfig.OneOfRule(validXdebugModes),
+		"xdebugOutputDir":     appConfig.StringRule(""),
+	}
+
+	return appConfig.ValidateConfigFile(file, rules)
+}
+
+// anonymizeFieldsRule Requires the value to be a list of columns in the form "table.column"
+func anonymizeFieldsRule(value interface{}) error {
+
+	err := appConfig.StringListRule([]string{})(value)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range value.([]interface{}) {
+		if !validAnonymizeField.MatchString(field.(string)) {
+			return fmt.Errorf("%q must be in the form \"table.column\", such as \"users.user_email\"", field)
+		}
+	}
+
+	return nil
+}
+
+// phpExtensionsRule Requires the value to be a list of PHP extension names
+func phpExtensionsRule(value interface{}) error {
+
+	err := appConfig.StringListRule([]string{})(value)
+	if err != nil {
+		return err
+	}
+
+	for _, extension := range value.([]interface{}) {
+		if !validPHPExtension.MatchString(extension.(string)) {
+			return fmt.Errorf("%q isn't a valid PHP extension name such as \"bcmath\"", extension)
+		}
+	}
+
+	return nil
+}
+
+// healthPathRule Requires the value to be a path on the site starting with a slash, such as "/health", rather than a full URL
+func healthPathRule(value interface{}) error {
+
+	healthPath, ok := value.(string)
+	if !ok || (len(healthPath) > 0 && !strings.HasPrefix(healthPath, "/")) || strings.ContainsAny(healthPath, " \t") {
+		return fmt.Errorf("must be a path on the site such as \"/health\"")
+	}
+
+	return nil
+}
+
+// subdirectoryRule Requires the value to be a path WordPress can be installed in
+func subdirectoryRule(value interface{}) error {
+
+	subdirectory, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a string such as \"wp\"")
+	}
+
+	subdirectory = strings.Trim(subdirectory, "/")
+
+	if len(subdirectory) > 0 && !validSubdirectory.MatchString(subdirectory) {
+		return fmt.Errorf("%q isn't a valid subdirectory", subdirectory)
+	}
+
+	return nil
+}
+
+// tablePrefixRule Requires the value to be a table prefix WordPress accepts
+func tablePrefixRule(value interface{}) error {
+
+	tablePrefix, ok := value.(string)
+	if !ok || !validTablePrefix.MatchString(tablePrefix) {
+		return fmt.Errorf("must contain only letters, numbers and underscores, such as \"wp_\"")
+	}
+
+	return nil
+}
+
+// timezoneRule Requires the value to be a timezone name or UTC offset
+func timezoneRule(value interface{}) error {
+
+	timezone, ok := value.(string)
+	if !ok || (len(timezone) > 0 && !isValidTimezone(timezone)) {
+		return fmt.Errorf("must be a timezone such as \"Europe/Berlin\" or an offset such as \"UTC-5\"")
+	}
+
+	return nil
+}
+
+// usersRule Requires the value to be a list of users, each with a username and any role and email valid
+func usersRule(value interface{}) error {
+
+	users, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("must be a list of users")
+	}
+
+	validate := validator.New()
+
+	for i, item := range users {
+
+		user, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("user %d must be an object", i+1)
+		}
+
+		for key, field := range user {
+
+			fieldValue, ok := field.(string)
+
+			switch {
+			case !appConfig.CheckString(key, []string{"username", "email", "role", "password"}):
+				return fmt.Errorf("user %d has the unknown key %s", i+1, key)
+			case !ok:
+				return fmt.Errorf("the %s of user %d must be a string", key, i+1)
+			case key == "role" && !appConfig.CheckString(fieldValue, appConfig.ValidRoles):
+				return fmt.Errorf("the role of user %d must be one of %s", i+1, strings.Join(appConfig.ValidRoles, ", "))
+			case key == "email" && validate.Var(fieldValue, "email") != nil:
+				return fmt.Errorf("the email %q of user %d isn't valid", fieldValue, i+1)
+			}
+		}
+
+		if username, _ := user["username"].(string); len(username) == 0 {
+			return fmt.Errorf("user %d must have a username", i+1)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,647 @@
+package site
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/docker"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// The credentials of every site's database, which can only be reached from the site's containers unless it is exposed
+const (
+	databaseName         = "wordpress"
+	databaseUser         = "wordpress"
+	databasePassword     = "wordpress"
+	databaseRootPassword = "password"
+)
+
+// databaseDumper Dumps a database to the export file, which is mounted with the given mounts, returning the exit code
+// and output of the command
+type databaseDumper func(exportFile string, exportMounts []mount.Mount, tables, excludeTables []string) (int64, string, error)
+
+// DatabaseConnectionFormats Are the formats GetDatabaseConnectionString can return
+var DatabaseConnectionFormats = []string{
+	"url",
+	"mysql",
+	"pdo",
+	"jdbc",
+}
+
+// ImportDatabase Imports the given SQL file into the site's database, replacing any tables it contains
+func (s *Site) ImportDatabase(file string) error {
+
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(s.StaticConfig.WorkingDirectory, file)
+	}
+
+	fileInfo, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	if fileInfo.IsDir() {
+		return fmt.Errorf("%s is a directory. Please specify a .sql file to import", file)
+	}
+
+	isGzipped, err := isGzipFile(file)
+	if err != nil {
+		return err
+	}
+
+	if isGzipped {
+		return s.importGzippedDatabase(file)
+	}
+
+	// Tables and their indexes usually take up more room in the database than in the dump
+	err = checkDiskSpace(path.Join(s.StaticConfig.SiteDirectory, "database"), uint64(fileInfo.Size())*2)
+	if err != nil {
+		return err
+	}
+
+	importFile := path.Join("/tmp", "kana", filepath.Base(file))
+
+	importMounts := []mount.Mount{
+		{
+			Type:     mount.TypeBind,
+			Source:   file,
+			Target:   importFile,
+			ReadOnly: true,
+		},
+	}
+
+	importCommand := []string{
+		"db",
+		"import",
+		importFile,
+	}
+
+	statusCode, output, err := s.runWPCli(importCommand, importMounts)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 0 {
+		return fmt.Errorf("unable to import %s: %s", file, output)
+	}
+
+	return nil
+}
+
+// ImportDatabaseFromURL Downloads a SQL file, optionally gzipped, from the given URL and imports it into the site's database,
+// replacing the source site's URL with the local one. The header, if given, is sent with the request in "Name: value" form.
+func (s *Site) ImportDatabaseFromURL(url, header string) error {
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(header) > 0 {
+		name, value, found := strings.Cut(header, ":")
+		if !found {
+			return fmt.Errorf("invalid header %q. Please use the form \"Name: value\"", header)
+		}
+
+		request.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	client, err := appConfig.NewHTTPClient(s.DynamicConfig)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to download %s: %s", url, response.Status)
+	}
+
+	if response.ContentLength > 0 {
+		err = checkDiskSpace(os.TempDir(), uint64(response.ContentLength))
+		if err != nil {
+			return err
+		}
+	}
+
+	importFile, err := os.CreateTemp("", "kana-import-*.sql")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(importFile.Name())
+
+	err = writeSQL(importFile, response.Body)
+	if err != nil {
+		importFile.Close()
+		return err
+	}
+
+	err = importFile.Close()
+	if err != nil {
+		return err
+	}
+
+	err = shareTempFile(importFile.Name())
+	if err != nil {
+		return err
+	}
+
+	err = s.ImportDatabase(importFile.Name())
+	if err != nil {
+		return err
+	}
+
+	return s.replaceImportedURL()
+}
+
+// importGzippedDatabase Decompresses a .sql.gz file to a temporary file and imports it into the site's database
+func (s *Site) importGzippedDatabase(file string) error {
+
+	gzippedFile, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer gzippedFile.Close()
+
+	importFile, err := os.CreateTemp("", "kana-import-*.sql")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(importFile.Name())
+
+	err = writeSQL(importFile, gzippedFile)
+	if err != nil {
+		importFile.Close()
+		return fmt.Errorf("unable to decompress %s: %s", file, err)
+	}
+
+	err = importFile.Close()
+	if err != nil {
+		return err
+	}
+
+	err = shareTempFile(importFile.Name())
+	if err != nil {
+		return err
+	}
+
+	return s.ImportDatabase(importFile.Name())
+}
+
+// isGzipFile Returns true if the file starts with the gzip header, whatever its extension
+func isGzipFile(file string) (bool, error) {
+
+	header := make([]byte, 2)
+
+	openFile, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer openFile.Close()
+
+	n, err := io.ReadFull(openFile, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false, err
+	}
+
+	return hasGzipHeader(header[:n]), nil
+}
+
+// hasGzipHeader Returns true if the bytes start with the gzip magic number
+func hasGzipHeader(header []byte) bool {
+	return len(header) >= 2 && header[0] == 0x1f && header[1] == 0x8b
+}
+
+// writeSQL Copies the SQL from the reader to the writer, decompressing it first if it is gzipped
+func writeSQL(writer io.Writer, reader io.Reader) error {
+
+	bufferedReader := bufio.NewReader(reader)
+
+	magic, err := bufferedReader.Peek(2)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	sqlReader := io.Reader(bufferedReader)
+
+	if hasGzipHeader(magic) {
+		gzipReader, err := gzip.NewReader(bufferedReader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+
+		sqlReader = gzipReader
+	}
+
+	_, err = io.Copy(writer, sqlReader)
+
+	return err
+}
+
+// getSiteURLOption Returns the site's URL the way WordPress saves it in the siteurl option, without a trailing slash.
+// Replacing a URL without a slash with one that has it would double the slash in every path after it.
+func (s *Site) getSiteURLOption() string {
+	return strings.TrimSuffix(s.GetURL(false), "/")
+}
+
+// replaceImportedURL Replaces the URL of the site an imported database came from with the site's own URL
+func (s *Site) replaceImportedURL() error {
+
+	statusCode, importedURL, err := s.runWPCli([]string{"option", "get", "siteurl"}, []mount.Mount{})
+	if err != nil || statusCode != 0 {
+		return err
+	}
+
+	importedURL = strings.TrimSpace(importedURL)
+	siteURL := s.getSiteURLOption()
+
+	if len(importedURL) == 0 || importedURL == siteURL {
+		return nil
+	}
+
+	console.Info("Replacing %s with %s...", importedURL, siteURL)
+
+	searchReplaceCommand := []string{
+		"search-replace",
+		importedURL,
+		siteURL,
+		"--all-tables",
+	}
+
+	statusCode, output, err := s.runWPCli(searchReplaceCommand, []mount.Mount{})
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 0 {
+		return fmt.Errorf("unable to replace %s with %s: %s", importedURL, siteURL, output)
+	}
+
+	return nil
+}
+
+// ExportDatabase Exports the site's database to the given SQL file, replacing the file if it already exists.
+// If tables is set only those tables are exported and any tables in excludeTables are left out.
+// Files ending in .gz are compressed with gzip.
+func (s *Site) ExportDatabase(file string, tables, excludeTables []string) error {
+	return s.exportDatabase(file, tables, excludeTables, s.dumpSiteDatabase)
+}
+
+// exportDatabase Exports a database to the given file with the dumper, compressing it if the file ends in .gz
+func (s *Site) exportDatabase(file string, tables, excludeTables []string, dump databaseDumper) error {
+
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(s.StaticConfig.WorkingDirectory, file)
+	}
+
+	if strings.HasSuffix(file, ".gz") {
+		return s.exportGzippedDatabase(file, tables, excludeTables, dump)
+	}
+
+	return s.exportSQL(file, tables, excludeTables, dump)
+}
+
+// exportGzippedDatabase Exports a database to a temporary file next to the given one and compresses it into the file
+func (s *Site) exportGzippedDatabase(file string, tables, excludeTables []string, dump databaseDumper) error {
+
+	sqlFile, err := os.CreateTemp(filepath.Dir(file), "kana-export-*.sql")
+	if err != nil {
+		return err
+	}
+
+	sqlFile.Close()
+	defer os.Remove(sqlFile.Name())
+
+	err = s.exportSQL(sqlFile.Name(), tables, excludeTables, dump)
+	if err != nil {
+		return err
+	}
+
+	return replaceFile(file, func(tempFile string) error {
+		return gzipFile(sqlFile.Name(), tempFile)
+	})
+}
+
+// gzipFile Compresses the source file into the target file, replacing its contents
+func gzipFile(source, target string) error {
+
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	targetFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gzipWriter := gzip.NewWriter(targetFile)
+
+	_, err = io.Copy(gzipWriter, sourceFile)
+
+	if closeErr := gzipWriter.Close(); err == nil {
+		err = closeErr
+	}
+
+	if closeErr := targetFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// exportSQL Exports a database to the given SQL file with the dumper
+func (s *Site) exportSQL(file string, tables, excludeTables []string, dump databaseDumper) error {
+
+	if len(tables) > 0 || len(excludeTables) > 0 {
+		err := s.validateTables(append(append([]string{}, tables...), excludeTables...))
+		if err != nil {
+			return err
+		}
+	}
+
+	databaseSize, err := s.getDatabaseSize()
+	if err != nil {
+		return err
+	}
+
+	// Dumps are usually smaller than the database but leave some room in case they aren't
+	err = checkDiskSpace(filepath.Dir(file), databaseSize+databaseSize/10)
+	if err != nil {
+		return err
+	}
+
+	return replaceFile(file, func(tempFile string) error {
+
+		// The CLI container doesn't run as the current user so the file has to be writable before it can be exported to
+		err := os.Chmod(tempFile, 0666)
+		if err != nil {
+			return err
+		}
+
+		exportFile := path.Join("/tmp", "kana", filepath.Base(tempFile))
+
+		exportMounts := []mount.Mount{
+			{
+				Type:   mount.TypeBind,
+				Source: tempFile,
+				Target: exportFile,
+			},
+		}
+
+		statusCode, output, err := dump(exportFile, exportMounts, tables, excludeTables)
+		if err == nil && statusCode != 0 {
+			err = fmt.Errorf("unable to export the database to %s: %s", file, output)
+		}
+
+		return err
+	})
+}
+
+// dumpSiteDatabase Dumps the site's database with wp-cli
+func (s *Site) dumpSiteDatabase(exportFile string, exportMounts []mount.Mount, tables, excludeTables []string) (int64, string, error) {
+
+	exportCommand := []string{
+		"db",
+		"export",
+		exportFile,
+	}
+
+	if len(tables) > 0 {
+		exportCommand = append(exportCommand, fmt.Sprintf("--tables=%s", strings.Join(tables, ",")))
+	}
+
+	if len(excludeTables) > 0 {
+		exportCommand = append(exportCommand, fmt.Sprintf("--exclude_tables=%s", strings.Join(excludeTables, ",")))
+	}
+
+	return s.runWPCli(exportCommand, exportMounts)
+}
+
+type TableRows struct {
+	Name string `json:"name"`
+	Rows uint64 `json:"rows"`
+}
+
+const tableNamesQuery = "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() ORDER BY TABLE_NAME"
+
+// baseTableNamesQuery Leaves out views, which can't be copied like tables
+const baseTableNamesQuery = "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
+
+// GetTables Returns the name and exact row count of each table in the site's database, sorted by name
+func (s *Site) GetTables() ([]TableRows, error) {
+
+	tables := []TableRows{}
+
+	results, err := s.QueryDatabase(tableNamesQuery)
+	if err != nil {
+		return tables, err
+	}
+
+	if len(results[0].Rows) == 0 {
+		return tables, nil
+	}
+
+	// information_schema only estimates row counts for InnoDB tables so count them all in a single query instead
+	countQueries := []string{}
+
+	for _, row := range results[0].Rows {
+		countQueries = append(countQueries, fmt.Sprintf(
+			"SELECT '%s', COUNT(*) FROM `%s`",
+			strings.ReplaceAll(row[0], "'", "''"),
+			strings.ReplaceAll(row[0], "`", "``")))
+	}
+
+	results, err = s.QueryDatabase(strings.Join(countQueries, " UNION ALL "))
+	if err != nil {
+		return tables, err
+	}
+
+	for _, row := range results[0].Rows {
+
+		if len(row) < 2 {
+			continue
+		}
+
+		rows, err := strconv.ParseUint(row[1], 10, 64)
+		if err != nil {
+			return tables, fmt.Errorf("unable to read the row count of table %s: %s", row[0], err)
+		}
+
+		tables = append(tables, TableRows{
+			Name: row[0],
+			Rows: rows,
+		})
+	}
+
+	return tables, nil
+}
+
+// validateTables Returns an error listing any of the given tables that don't exist in the site's database
+func (s *Site) validateTables(tables []string) error {
+
+	results, err := s.QueryDatabase(tableNamesQuery)
+	if err != nil {
+		return err
+	}
+
+	existingTables := make(map[string]bool)
+
+	for _, row := range results[0].Rows {
+		existingTables[row[0]] = true
+	}
+
+	missingTables := []string{}
+
+	for _, table := range tables {
+		if !existingTables[table] {
+			missingTables = append(missingTables, table)
+		}
+	}
+
+	if len(missingTables) > 0 {
+		return fmt.Errorf("the database doesn't have the table(s) %s. Run 'kana db tables' to see the available tables", strings.Join(missingTables, ", "))
+	}
+
+	return nil
+}
+
+// OptimizeDatabase Optimizes and repairs the site's database tables, optionally removing transients first.
+// Returns the table-by-table results of each step.
+func (s *Site) OptimizeDatabase(deleteTransients bool) (string, error) {
+
+	commands := [][]string{
+		{"db", "optimize"},
+		{"db", "repair"},
+	}
+
+	if deleteTransients {
+		commands = append([][]string{{"transient", "delete", "--all"}}, commands...)
+	}
+
+	results := ""
+
+	for _, command := range commands {
+
+		statusCode, output, err := s.runWPCli(command, []mount.Mount{})
+		if err != nil {
+			return results, err
+		}
+
+		if statusCode != 0 {
+			return results, fmt.Errorf("unable to run wp %s: %s", strings.Join(command, " "), output)
+		}
+
+		results += output
+	}
+
+	return results, nil
+}
+
+// GetDatabaseConnection Returns a connection string for the site's database if its port is exposed to the host
+func (s *Site) GetDatabaseConnection() (string, bool) {
+
+	connection, err := s.GetDatabaseConnectionString("url")
+
+	return connection, err == nil
+}
+
+// GetDatabaseConnectionString Returns the details for connecting to the site's database from the host in the given
+// format: a "url", arguments for the "mysql" client, a "pdo" DSN or a "jdbc" URL. The database must be exposed.
+func (s *Site) GetDatabaseConnectionString(format string) (string, error) {
+
+	if !appConfig.CheckString(format, DatabaseConnectionFormats) {
+		return "", fmt.Errorf("invalid format %q. Please use one of %s", format, strings.Join(DatabaseConnectionFormats, ", "))
+	}
+
+	port := s.dockerClient.ContainerGetHostPort(s.getContainerName("database"), "3306")
+	if len(port) == 0 {
+		return "", fmt.Errorf("the database can't be reached from your computer. Please set database.expose to true in .kana.json and restart the site with 'kana start --fresh'")
+	}
+
+	switch format {
+	case "mysql":
+		return fmt.Sprintf("--host=127.0.0.1 --port=%s --user=%s --password=%s %s", port, databaseUser, databasePassword, databaseName), nil
+	case "pdo":
+		// PDO takes the user and password separately from the DSN
+		return fmt.Sprintf("mysql:host=127.0.0.1;port=%s;dbname=%s", port, databaseName), nil
+	case "jdbc":
+		return fmt.Sprintf("jdbc:mysql://127.0.0.1:%s/%s?user=%s&password=%s", port, databaseName, databaseUser, databasePassword), nil
+	default:
+		return fmt.Sprintf("mysql://%s:%s@127.0.0.1:%s/%s", databaseUser, databasePassword, port, databaseName), nil
+	}
+}
+
+// getDatabasePorts Returns the port binding for the database container if the "database.expose" option is set.
+// The "database.port" option is used if it is free, otherwise a free port is chosen.
+func (s *Site) getDatabasePorts() ([]docker.ExposedPorts, error) {
+
+	if !s.Settings.Database.Expose {
+		return []docker.ExposedPorts{}, nil
+	}
+
+	// A running database already has its port and would look like it's using the configured one
+	if _, isRunning := s.dockerClient.IsContainerRunning(s.getContainerName("database")); isRunning {
+		return []docker.ExposedPorts{}, nil
+	}
+
+	port := s.Settings.Database.Port
+
+	if port > 0 {
+		listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		if err == nil {
+			listener.Close()
+		} else {
+			console.Warn("Port %d is already in use. The database will use a free port instead.", port)
+			port = 0
+		}
+	}
+
+	if port == 0 {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return []docker.ExposedPorts{}, err
+		}
+
+		port = listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+	}
+
+	return []docker.ExposedPorts{
+		{Port: "3306", Protocol: "tcp", HostIP: "127.0.0.1", HostPort: strconv.Itoa(port)},
+	}, nil
+}
+
+// seedDatabase Imports the SQL files set in the site's "database.seed" option in the order they are listed
+func (s *Site) seedDatabase() error {
+
+	for _, seedFile := range s.Settings.Database.Seed {
+
+		console.Info("Importing %s...", seedFile)
+
+		err := s.ImportDatabase(seedFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
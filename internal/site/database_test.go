@@ -0,0 +1,37 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+)
+
+func TestGetSiteURLOption(t *testing.T) {
+
+	tests := []struct {
+		subdirectory string
+		insecure     bool
+		url          string
+	}{
+		{"", false, "https://test.sites.kana.li"},
+		{"", true, "http://test.sites.kana.li"},
+		{"wp", false, "https://test.sites.kana.li/wp"},
+	}
+
+	for _, test := range tests {
+
+		site := &Site{
+			StaticConfig: appConfig.StaticConfig{AppDomain: "sites.kana.li"},
+		}
+
+		site.setSiteName("test")
+		site.Settings.Subdirectory = test.subdirectory
+		site.Settings.Insecure = test.insecure
+
+		url := site.getSiteURLOption()
+
+		if url != test.url {
+			t.Errorf("getSiteURLOption() with subdirectory %q returned %q. Expected %q", test.subdirectory, url, test.url)
+		}
+	}
+}
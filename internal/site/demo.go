@@ -0,0 +1,137 @@
+package site
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// themeUnitTestDataURL Is the WordPress theme unit test data, imported when the "demoContentFile" option isn't set
+const themeUnitTestDataURL = "https://raw.githubusercontent.com/WordPress/theme-test-data/master/themeunittestdata.wordpress.xml"
+
+// ImportDemoContent Imports the WXR file in the site's "demoContentFile" option, or the WordPress theme unit test data
+// if it isn't set, so themes have posts, pages and menus to show. The importer plugin is only kept if it was already installed.
+func (s *Site) ImportDemoContent() error {
+
+	demoFile, err := s.getDemoContentFile()
+	if err != nil {
+		return err
+	}
+
+	if len(s.Settings.DemoContentFile) == 0 {
+		defer os.Remove(demoFile)
+	}
+
+	statusCode, _, err := s.runWPCli([]string{"plugin", "is-installed", "wordpress-importer"}, []mount.Mount{})
+	if err != nil {
+		return err
+	}
+
+	hasImporter := statusCode == 0
+
+	statusCode, output, err := s.runWPCli([]string{"plugin", "install", "wordpress-importer", "--activate"}, []mount.Mount{})
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 0 {
+		return fmt.Errorf("unable to install the WordPress importer: %s", strings.TrimSpace(output))
+	}
+
+	spinner := console.StartSpinner("Importing demo content...")
+
+	importFile := path.Join("/tmp", "kana", filepath.Base(demoFile))
+
+	importMounts := []mount.Mount{
+		{
+			Type:     mount.TypeBind,
+			Source:   demoFile,
+			Target:   importFile,
+			ReadOnly: true,
+		},
+	}
+
+	statusCode, output, err = s.runWPCli([]string{"import", importFile, "--authors=create"}, importMounts)
+
+	spinner.Stop()
+
+	if err == nil && statusCode != 0 {
+		err = fmt.Errorf("unable to import the demo content: %s", strings.TrimSpace(output))
+	}
+
+	if !hasImporter {
+		_, _, uninstallErr := s.runWPCli([]string{"plugin", "uninstall", "wordpress-importer", "--deactivate"}, []mount.Mount{})
+		if uninstallErr != nil {
+			console.Debug("Unable to remove the WordPress importer: %s", uninstallErr)
+		}
+	}
+
+	return err
+}
+
+// getDemoContentFile Returns the absolute path of the WXR file in the "demoContentFile" option, relative to the site's
+// folder, or downloads the theme unit test data to a temporary file if the option isn't set
+func (s *Site) getDemoContentFile() (string, error) {
+
+	demoFile := s.Settings.DemoContentFile
+
+	if len(demoFile) > 0 {
+
+		if !filepath.IsAbs(demoFile) {
+			demoFile = filepath.Join(s.StaticConfig.WorkingDirectory, demoFile)
+		}
+
+		fileInfo, err := os.Stat(demoFile)
+		if err != nil || fileInfo.IsDir() {
+			return "", fmt.Errorf("the demo content file %s doesn't exist. Please check the demoContentFile option", demoFile)
+		}
+
+		return demoFile, nil
+	}
+
+	client, err := appConfig.NewHTTPClient(s.DynamicConfig)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := client.Get(themeUnitTestDataURL)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to download the theme unit test data: %s", response.Status)
+	}
+
+	downloadFile, err := os.CreateTemp("", "kana-demo-*.xml")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(downloadFile, response.Body)
+
+	if closeErr := downloadFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = shareTempFile(downloadFile.Name())
+	}
+
+	if err != nil {
+		os.Remove(downloadFile.Name())
+		return "", err
+	}
+
+	return downloadFile.Name(), nil
+}
@@ -0,0 +1,146 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// checkDiskSpace Returns an error if the filesystem holding the directory has less than the required number of bytes free
+func checkDiskSpace(directory string, required uint64) error {
+
+	available, err := getAvailableDiskSpace(directory)
+	if err != nil {
+		return err
+	}
+
+	if available < required {
+		return fmt.Errorf("not enough disk space in %s. %s is needed but only %s is available", directory, FormatBytes(required), FormatBytes(available))
+	}
+
+	return nil
+}
+
+// shareTempFile Makes a temporary file readable by the containers, which don't run as the current user, as temp files
+// are only readable by the user that created them
+func shareTempFile(file string) error {
+	return os.Chmod(file, 0644)
+}
+
+// replaceFile Writes a temporary file next to the target with the given function and moves it over the target once
+// it succeeds, so a failed write never empties or removes a file that was already there
+func replaceFile(target string, write func(tempFile string) error) error {
+
+	tempFile, err := os.CreateTemp(filepath.Dir(target), fmt.Sprintf(".%s-*", filepath.Base(target)))
+	if err != nil {
+		return err
+	}
+
+	err = tempFile.Close()
+	if err == nil {
+		err = write(tempFile.Name())
+	}
+
+	// Temp files are only readable by the current user but exports should keep the usual permissions
+	if err == nil {
+		err = os.Chmod(tempFile.Name(), 0644)
+	}
+
+	if err == nil {
+		err = os.Rename(tempFile.Name(), target)
+	}
+
+	if err != nil {
+		os.Remove(tempFile.Name())
+	}
+
+	return err
+}
+
+// getDatabaseSize Returns the size of the site's database in bytes
+func (s *Site) getDatabaseSize() (uint64, error) {
+
+	statusCode, output, err := s.runWPCli([]string{"db", "size", "--size_format=b"}, []mount.Mount{})
+	if err != nil {
+		return 0, err
+	}
+
+	if statusCode != 0 {
+		return 0, fmt.Errorf("unable to get the size of the database: %s", strings.TrimSpace(output))
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(output), 10, 64)
+}
+
+type TableSize struct {
+	Name string `json:"name"`
+	Size uint64 `json:"size"`
+}
+
+type DatabaseSize struct {
+	Total  uint64      `json:"total"`
+	Tables []TableSize `json:"tables"`
+}
+
+const tableSizesQuery = "SELECT TABLE_NAME AS name, DATA_LENGTH + INDEX_LENGTH AS size FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE()"
+
+// GetDatabaseSizes Returns the total size of the site's database and the size of each table, largest first
+func (s *Site) GetDatabaseSizes() (DatabaseSize, error) {
+
+	databaseSize := DatabaseSize{
+		Tables: []TableSize{},
+	}
+
+	results, err := s.QueryDatabase(tableSizesQuery)
+	if err != nil {
+		return databaseSize, err
+	}
+
+	for _, row := range results[0].Rows {
+
+		if len(row) < 2 {
+			continue
+		}
+
+		size, err := strconv.ParseUint(row[1], 10, 64)
+		if err != nil {
+			return databaseSize, fmt.Errorf("unable to read the size of table %s: %s", row[0], err)
+		}
+
+		databaseSize.Total += size
+		databaseSize.Tables = append(databaseSize.Tables, TableSize{
+			Name: row[0],
+			Size: size,
+		})
+	}
+
+	sort.SliceStable(databaseSize.Tables, func(i, j int) bool {
+		return databaseSize.Tables[i].Size > databaseSize.Tables[j].Size
+	})
+
+	return databaseSize, nil
+}
+
+// FormatBytes Returns the number of bytes in a human readable form such as 1.5 GB
+func FormatBytes(bytes uint64) string {
+
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	size := float64(bytes)
+	unit := 0
+
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	return fmt.Sprintf("%.1f %s", size, units[unit])
+}
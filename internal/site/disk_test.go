@@ -0,0 +1,48 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplaceFile(t *testing.T) {
+
+	directory := t.TempDir()
+	target := filepath.Join(directory, "export.sql")
+
+	err := os.WriteFile(target, []byte("original"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = replaceFile(target, func(tempFile string) error {
+		return fmt.Errorf("export failed")
+	})
+	if err == nil {
+		t.Errorf("replaceFile() returned no error when the write failed")
+	}
+
+	contents, err := os.ReadFile(target)
+	if err != nil || string(contents) != "original" {
+		t.Errorf("replaceFile() changed the target when the write failed. Received %q, %v", contents, err)
+	}
+
+	err = replaceFile(target, func(tempFile string) error {
+		return os.WriteFile(tempFile, []byte("replaced"), 0600)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err = os.ReadFile(target)
+	if err != nil || string(contents) != "replaced" {
+		t.Errorf("replaceFile() didn't replace the target. Received %q, %v", contents, err)
+	}
+
+	files, err := os.ReadDir(directory)
+	if err != nil || len(files) != 1 {
+		t.Errorf("replaceFile() left temporary files behind: %v, %v", files, err)
+	}
+}
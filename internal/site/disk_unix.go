@@ -0,0 +1,20 @@
+//go:build !windows
+
+package site
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// getAvailableDiskSpace Returns the number of bytes available to the current user on the filesystem holding the directory
+func getAvailableDiskSpace(directory string) (uint64, error) {
+
+	var stat unix.Statfs_t
+
+	err := unix.Statfs(directory, &stat)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
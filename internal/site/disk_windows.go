@@ -0,0 +1,25 @@
+//go:build windows
+
+package site
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// getAvailableDiskSpace Returns the number of bytes available to the current user on the volume holding the directory
+func getAvailableDiskSpace(directory string) (uint64, error) {
+
+	path, err := windows.UTF16PtrFromString(directory)
+	if err != nil {
+		return 0, err
+	}
+
+	var available uint64
+
+	err = windows.GetDiskFreeSpaceEx(path, &available, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return available, nil
+}
@@ -0,0 +1,80 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana-cli/internal/docker"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// entrypointTarget Is where the script in the site's "entrypoint" option is mounted in the WordPress containers
+const entrypointTarget = "/docker-entrypoint.d/kana-entrypoint.sh"
+
+// getEntrypointScript Returns the absolute path of the script in the site's "entrypoint" option, relative to the site's
+// folder, after checking it can be run. Returns an empty string if the option isn't set.
+func (s *Site) getEntrypointScript() (string, error) {
+
+	script := s.Settings.Entrypoint
+	if len(script) == 0 {
+		return "", nil
+	}
+
+	if !filepath.IsAbs(script) {
+		script = filepath.Join(s.StaticConfig.WorkingDirectory, script)
+	}
+
+	scriptInfo, err := os.Stat(script)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the site's entrypoint script: %s", err)
+	}
+
+	if !scriptInfo.Mode().IsRegular() {
+		return "", fmt.Errorf("the site's entrypoint script %s isn't a file", script)
+	}
+
+	// The script is run directly so its shebang picks the shell, which needs it to be executable
+	if scriptInfo.Mode().Perm()&0111 == 0 {
+		return "", fmt.Errorf("the site's entrypoint script %s isn't executable. Please run 'chmod +x %s'", script, script)
+	}
+
+	return script, nil
+}
+
+// setEntrypoint Mounts the script into the container and runs it before the image's own entrypoint, which the
+// WordPress image doesn't run any initialization scripts from
+func (s *Site) setEntrypoint(container *docker.ContainerConfig, script string) error {
+
+	imageEntrypoint, imageCommand, err := s.dockerClient.GetImageCommand(container.Image)
+	if err != nil {
+		return fmt.Errorf("unable to read the entrypoint of %s to run the script in the site's entrypoint option before it: %s", container.Image, err)
+	}
+
+	// Replacing the entrypoint also drops the image's command so it is passed along unless the site replaces it
+	if len(container.Command) == 0 {
+		container.Command = imageCommand
+	}
+
+	// The arguments after the shell's own name are the image's entrypoint and command, which are run once the script succeeds
+	container.Entrypoint = append([]string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf("%s && exec \"$@\"", entrypointTarget),
+		"kana-entrypoint",
+	}, imageEntrypoint...)
+
+	// The containers for extra PHP versions share their mounts with the main container so they can't be appended to
+	volumes := make([]mount.Mount, 0, len(container.Volumes)+1)
+	volumes = append(volumes, container.Volumes...)
+
+	container.Volumes = append(volumes, mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   script,
+		Target:   entrypointTarget,
+		ReadOnly: true,
+	})
+
+	return nil
+}
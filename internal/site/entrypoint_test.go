@@ -0,0 +1,70 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+)
+
+func TestGetEntrypointScript(t *testing.T) {
+
+	workingDirectory := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(workingDirectory, ".kana", "scripts"), 0750)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(workingDirectory, ".kana", "entrypoint.sh"), []byte("#!/bin/sh\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(workingDirectory, ".kana", "readonly.sh"), []byte("#!/bin/sh\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		entrypoint string
+		script     string
+		err        string
+	}{
+		{"", "", ""},
+		{".kana/entrypoint.sh", filepath.Join(workingDirectory, ".kana", "entrypoint.sh"), ""},
+		{filepath.Join(workingDirectory, ".kana", "entrypoint.sh"), filepath.Join(workingDirectory, ".kana", "entrypoint.sh"), ""},
+		{".kana/scripts", "", "isn't a file"},
+		{".kana/readonly.sh", "", "isn't executable"},
+		{".kana/missing.sh", "", "unable to read the site's entrypoint script"},
+	}
+
+	for _, test := range tests {
+
+		site := &Site{
+			StaticConfig: appConfig.StaticConfig{WorkingDirectory: workingDirectory},
+		}
+
+		site.Settings.Entrypoint = test.entrypoint
+
+		script, err := site.getEntrypointScript()
+
+		if len(test.err) > 0 {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("getEntrypointScript() with entrypoint %q returned error %v. Expected an error containing %q", test.entrypoint, err, test.err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("getEntrypointScript() with entrypoint %q returned error %v", test.entrypoint, err)
+		}
+
+		if script != test.script {
+			t.Errorf("getEntrypointScript() with entrypoint %q returned %q. Expected %q", test.entrypoint, script, test.script)
+		}
+	}
+}
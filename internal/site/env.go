@@ -0,0 +1,36 @@
+package site
+
+type EnvironmentVariable struct {
+	Name  string
+	Value string
+}
+
+// GetEnvironment Returns the variables external tools need to find and connect to the site's containers
+func (s *Site) GetEnvironment() []EnvironmentVariable {
+
+	databaseContainer := s.getContainerName("database")
+
+	environment := []EnvironmentVariable{
+		{"KANA_SITE_NAME", s.StaticConfig.SiteName},
+		{"KANA_SITE_URL", s.GetURL(false)},
+		{"KANA_SITE_DIRECTORY", s.StaticConfig.SiteDirectory},
+		{"KANA_NETWORK", s.getNetworkName()},
+		{"KANA_WORDPRESS_CONTAINER", s.getContainerName("wordpress")},
+		{"KANA_DATABASE_CONTAINER", databaseContainer},
+		{"KANA_DB_HOST", databaseContainer},
+		{"KANA_DB_PORT", "3306"},
+		{"KANA_DB_USER", databaseUser},
+		{"KANA_DB_PASSWORD", databasePassword},
+		{"KANA_DB_NAME", databaseName},
+	}
+
+	// The database can only be reached from the host when its port is exposed
+	if port := s.dockerClient.ContainerGetHostPort(databaseContainer, "3306"); len(port) > 0 {
+		environment = append(environment,
+			EnvironmentVariable{"KANA_DB_HOST_ADDRESS", "127.0.0.1"},
+			EnvironmentVariable{"KANA_DB_HOST_PORT", port},
+		)
+	}
+
+	return environment
+}
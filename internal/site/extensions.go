@@ -0,0 +1,135 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// extensionHeaders Holds the header that marks the main file of a plugin or theme
+var extensionHeaders = map[string]string{
+	"plugins": "Plugin Name:",
+	"themes":  "Theme Name:",
+}
+
+// getExtensionMounts Returns a mount for each directory in the "directories" option, placing it in the wp-content
+// folder for the type (plugins or themes) under its own name
+func (s *Site) getExtensionMounts(siteType string) ([]mount.Mount, error) {
+
+	directories := s.Settings.Directories
+	if len(directories) == 0 {
+		return []mount.Mount{}, fmt.Errorf("sites with the %s type need a list of directories to mount in the \"directories\" option", siteType)
+	}
+
+	mounts := []mount.Mount{}
+	names := []string{}
+
+	for _, directory := range directories {
+
+		source := filepath.Join(s.StaticConfig.WorkingDirectory, directory)
+
+		relativePath, err := filepath.Rel(s.StaticConfig.WorkingDirectory, source)
+		if err != nil || relativePath == "." || strings.HasPrefix(relativePath, "..") {
+			return []mount.Mount{}, fmt.Errorf("the directory %q must be a folder inside %s", directory, s.StaticConfig.WorkingDirectory)
+		}
+
+		name := filepath.Base(source)
+
+		for _, existingName := range names {
+			if existingName == name {
+				return []mount.Mount{}, fmt.Errorf("more than one directory is named %s. Each %s needs a unique folder name", name, strings.TrimSuffix(siteType, "s"))
+			}
+		}
+
+		err = validateExtensionDirectory(source, siteType)
+		if err != nil {
+			return []mount.Mount{}, err
+		}
+
+		names = append(names, name)
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: source,
+			Target: path.Join(s.getWordPressPath(), "wp-content", siteType, name),
+		})
+	}
+
+	return mounts, nil
+}
+
+// getExtensionNames Returns the folder names of the plugins or themes mounted from the "directories" option
+func (s *Site) getExtensionNames() []string {
+
+	names := []string{}
+
+	for _, directory := range s.Settings.Directories {
+		names = append(names, filepath.Base(filepath.Join(s.StaticConfig.WorkingDirectory, directory)))
+	}
+
+	return names
+}
+
+// getMountedExtensions Returns the names of the plugins or themes, given as "plugin" or "theme", mounted from the site's
+// own folder. They aren't from WordPress.org so they can't be verified or updated from there.
+func (s *Site) getMountedExtensions(extensionType string) []string {
+
+	switch s.Settings.Type {
+	case extensionType:
+		return []string{s.StaticConfig.SiteName}
+	case extensionType + "s":
+		return s.getExtensionNames()
+	}
+
+	return []string{}
+}
+
+// validateExtensionDirectory Returns an error if the directory doesn't contain a plugin or theme with the header WordPress needs to find it
+func validateExtensionDirectory(directory, siteType string) error {
+
+	info, err := os.Stat(directory)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("the directory %s doesn't exist", directory)
+	}
+
+	header := extensionHeaders[siteType]
+	files := []string{filepath.Join(directory, "style.css")}
+
+	if siteType == "plugins" {
+		files, err = filepath.Glob(filepath.Join(directory, "*.php"))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		if hasFileHeader(file, header) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no %s header was found in %s. Please check it contains a %s", header, directory, strings.TrimSuffix(siteType, "s"))
+}
+
+// hasFileHeader Returns true if the header appears near the top of the file, where WordPress looks for it
+func hasFileHeader(file, header string) bool {
+
+	handle, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer handle.Close()
+
+	// WordPress only reads the first 8KB of a file for its headers
+	contents, err := io.ReadAll(io.LimitReader(handle, 8192))
+	if err != nil {
+		return false
+	}
+
+	return bytes.Contains(contents, []byte(header))
+}
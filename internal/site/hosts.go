@@ -0,0 +1,131 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+)
+
+// getHostsFile Returns the path to the system's hosts file
+func getHostsFile() string {
+
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
+	}
+
+	return "/etc/hosts"
+}
+
+// getHostsDomains Returns the domains of the site that should be in the hosts file
+func (s *Site) getHostsDomains() []string {
+
+	domains := []string{s.siteDomain}
+
+	for _, phpVersion := range s.getPHPVersions() {
+		domains = append(domains, s.getPHPVersionDomain(phpVersion))
+	}
+
+	return domains
+}
+
+// addHostsEntries Writes all of the site's domains to its block in the hosts file if any of them don't resolve.
+// Domains that resolve because of an earlier block are kept so adding one doesn't drop the others.
+func (s *Site) addHostsEntries() error {
+
+	domains := s.getHostsDomains()
+
+	for _, domain := range domains {
+		if _, err := net.LookupHost(domain); err != nil {
+			return s.updateHostsFile(domains)
+		}
+	}
+
+	return nil
+}
+
+// removeHostsEntries Removes any entries Kana added to the hosts file for the site
+func (s *Site) removeHostsEntries() error {
+	return s.updateHostsFile([]string{})
+}
+
+// updateHostsFile Replaces the site's block in the hosts file with entries for the given domains, removing the block if there are none
+func (s *Site) updateHostsFile(domains []string) error {
+
+	hostsFile := getHostsFile()
+
+	contents, err := os.ReadFile(hostsFile)
+	if err != nil {
+		return err
+	}
+
+	newContents := replaceHostsBlock(string(contents), s.StaticConfig.SiteName, domains)
+	if newContents == string(contents) {
+		return nil
+	}
+
+	return writeHostsFile(hostsFile, []byte(newContents))
+}
+
+// replaceHostsBlock Returns the contents of a hosts file with the Kana-managed block for the site replaced by entries for the given domains
+func replaceHostsBlock(contents, siteName string, domains []string) string {
+
+	startMarker := fmt.Sprintf("# Kana start: %s", siteName)
+	endMarker := fmt.Sprintf("# Kana end: %s", siteName)
+
+	lines := []string{}
+	inBlock := false
+
+	for _, line := range strings.Split(strings.TrimSuffix(contents, "\n"), "\n") {
+
+		switch strings.TrimSpace(line) {
+		case startMarker:
+			inBlock = true
+		case endMarker:
+			inBlock = false
+		default:
+			if !inBlock {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	if len(domains) > 0 {
+		lines = append(lines,
+			startMarker,
+			fmt.Sprintf("127.0.0.1 %s", strings.Join(domains, " ")),
+			fmt.Sprintf("::1 %s", strings.Join(domains, " ")),
+			endMarker,
+		)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// writeHostsFile Writes the hosts file, using sudo if the current user isn't allowed to
+func writeHostsFile(hostsFile string, contents []byte) error {
+
+	err := os.WriteFile(hostsFile, contents, 0644)
+	if err == nil || !os.IsPermission(err) || runtime.GOOS == "windows" {
+		return err
+	}
+
+	console.Info("Updating %s needs administrator access. You may be asked for your password.", hostsFile)
+
+	command := exec.Command("sudo", "tee", hostsFile)
+	command.Stdin = bytes.NewReader(contents)
+	command.Stderr = os.Stderr
+
+	err = command.Run()
+	if err != nil {
+		return fmt.Errorf("unable to update %s: %s", hostsFile, err)
+	}
+
+	return nil
+}
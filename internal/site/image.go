@@ -0,0 +1,42 @@
+package site
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+)
+
+// getWordPressImage Returns the image for a WordPress container running the given PHP version. If the site has a Dockerfile
+// it is built first, passing the PHP version as the PHP_VERSION build argument.
+func (s *Site) getWordPressImage(phpVersion string) (string, error) {
+
+	dockerfile := s.Settings.Dockerfile
+	if len(dockerfile) == 0 {
+		return appConfig.GetImage(s.DynamicConfig, "wordpress", phpVersion), nil
+	}
+
+	if !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(s.StaticConfig.WorkingDirectory, dockerfile)
+	}
+
+	contents, err := os.ReadFile(dockerfile)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the site's Dockerfile: %s", err)
+	}
+
+	// Tagging the image with a hash of the Dockerfile reuses the build until the Dockerfile changes
+	hash := fmt.Sprintf("%x", sha256.Sum256(contents))
+	tag := fmt.Sprintf("%s:php%s-%s", s.getContainerName("wordpress"), phpVersion, hash[:12])
+
+	err = s.dockerClient.BuildImage(dockerfile, tag, map[string]*string{
+		"PHP_VERSION": &phpVersion,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return tag, nil
+}
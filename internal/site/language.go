@@ -0,0 +1,101 @@
+package site
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+var validLocale = regexp.MustCompile(`^[a-z]{2,3}(_[A-Z]{2})?(_[a-z0-9]+)?$`)
+
+// InstallLanguage Installs the given WordPress language pack, optionally switching the site to it, and saves it to the site config
+func (s *Site) InstallLanguage(locale string, activate bool) error {
+
+	err := s.installLanguage(locale, activate)
+	if err != nil {
+		return err
+	}
+
+	languages := s.Settings.Languages
+
+	if !appConfig.CheckString(locale, languages) {
+		s.setSetting("languages", append(languages, locale))
+	}
+
+	if activate {
+		s.setSetting("language", locale)
+	}
+
+	return s.writeSiteConfig()
+}
+
+// GetLanguages Returns the locales of the language packs available for the site's version of WordPress and whether each is installed
+func (s *Site) GetLanguages() (string, error) {
+
+	statusCode, output, err := s.runWPCli([]string{"language", "core", "list", "--fields=language,english_name,status"}, []mount.Mount{})
+	if err != nil {
+		return output, err
+	}
+
+	if statusCode != 0 {
+		return output, fmt.Errorf("unable to list languages: %s", strings.TrimSpace(output))
+	}
+
+	return output, nil
+}
+
+// InstallDefaultLanguages Installs the language packs in the site's "languages" option and switches to its "language"
+func (s *Site) InstallDefaultLanguages() error {
+
+	language := s.Settings.Language
+
+	for _, locale := range s.Settings.Languages {
+
+		err := s.installLanguage(locale, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(language) == 0 {
+		return nil
+	}
+
+	return s.installLanguage(language, true)
+}
+
+// installLanguage Validates and installs a language pack, switching the site to it if asked
+func (s *Site) installLanguage(locale string, activate bool) error {
+
+	if !validLocale.MatchString(locale) {
+		return fmt.Errorf("invalid locale %q. Locales look like \"de_DE\" or \"fr_FR\". Run 'kana language list' to see the available languages", locale)
+	}
+
+	statusCode, output, err := s.runWPCli([]string{"language", "core", "install", locale}, []mount.Mount{})
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 0 {
+		return fmt.Errorf("unable to install %s: %s. Run 'kana language list' to see the available languages", locale, strings.TrimSpace(output))
+	}
+
+	if !activate {
+		return nil
+	}
+
+	statusCode, output, err = s.runWPCli([]string{"site", "switch-language", locale}, []mount.Mount{})
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 0 {
+		return fmt.Errorf("unable to switch the site to %s: %s", locale, strings.TrimSpace(output))
+	}
+
+	return nil
+}
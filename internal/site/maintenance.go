@@ -0,0 +1,48 @@
+package site
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// SetMaintenanceMode Turns WordPress maintenance mode on or off for the site
+func (s *Site) SetMaintenanceMode(enable bool) error {
+
+	action := "deactivate"
+
+	if enable {
+		action = "activate"
+	}
+
+	statusCode, output, err := s.runWPCli([]string{"maintenance-mode", action}, []mount.Mount{})
+	if err != nil {
+		return err
+	}
+
+	// Activating twice fails but leaves the site in the mode that was asked for
+	if statusCode != 0 && !strings.Contains(output, "already") {
+		return fmt.Errorf("unable to %s maintenance mode: %s", action, strings.TrimSpace(output))
+	}
+
+	return nil
+}
+
+// IsMaintenanceModeActive Returns true if WordPress maintenance mode is on for the site
+func (s *Site) IsMaintenanceModeActive() (bool, error) {
+
+	statusCode, output, err := s.runWPCli([]string{"maintenance-mode", "is-active"}, []mount.Mount{})
+	if err != nil {
+		return false, err
+	}
+
+	switch statusCode {
+	case 0:
+		return true, nil
+	case 1:
+		return false, nil
+	}
+
+	return false, fmt.Errorf("unable to check maintenance mode: %s", strings.TrimSpace(output))
+}
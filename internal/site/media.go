@@ -0,0 +1,349 @@
+package site
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+)
+
+// regenerateProgress Matches the count wp-cli prints before each image it regenerates, such as "3/120"
+var regenerateProgress = regexp.MustCompile(`^(\d+)/(\d+)\s`)
+
+// terminalColor Matches the color codes wp-cli adds to its output when run in a terminal
+var terminalColor = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// regenerateWriter Turns the output of "wp media regenerate" into progress messages as it is printed
+type regenerateWriter struct {
+	spinner *console.Spinner
+	buffer  []byte
+	summary string
+	errors  []string
+}
+
+// getUploadsDirectory Returns the path on the host of the site's wp-content/uploads directory, which is the shared
+// folder in the "uploads.directory" option if it is set
+func (s *Site) getUploadsDirectory() string {
+
+	if sharedUploadsDirectory := s.getSharedUploadsDirectory(); len(sharedUploadsDirectory) > 0 {
+		return sharedUploadsDirectory
+	}
+
+	appDir := path.Join(s.StaticConfig.SiteDirectory, "app")
+
+	if s.IsLocalSite() {
+		appDir = path.Join(s.StaticConfig.WorkingDirectory, "wordpress")
+	}
+
+	return path.Join(appDir, s.getSubdirectory(), "wp-content", "uploads")
+}
+
+// ExportMedia Saves the site's uploads directory to the given .tar.gz file, replacing the file if it already exists.
+// Files are streamed into the archive one at a time so large media libraries aren't held in memory.
+func (s *Site) ExportMedia(file string) error {
+
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(s.StaticConfig.WorkingDirectory, file)
+	}
+
+	uploadsDirectory := s.getUploadsDirectory()
+
+	if _, err := os.Stat(uploadsDirectory); os.IsNotExist(err) {
+		return fmt.Errorf("the site doesn't have an uploads directory to export. Please run 'kana start' to create the site first")
+	}
+
+	return replaceFile(file, func(tempFile string) error {
+
+		archiveFile, err := os.OpenFile(tempFile, os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+
+		err = writeMediaArchive(archiveFile, uploadsDirectory)
+
+		closeErr := archiveFile.Close()
+		if err == nil {
+			err = closeErr
+		}
+
+		return err
+	})
+}
+
+// writeMediaArchive Writes the contents of the uploads directory to the writer as a gzipped tar archive,
+// with every path starting with "uploads/"
+func writeMediaArchive(writer io.Writer, uploadsDirectory string) error {
+
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err := filepath.WalkDir(uploadsDirectory, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Only directories and regular files are exported so links can't point outside of the uploads
+		if !entry.IsDir() && !entry.Type().IsRegular() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(filepath.Dir(uploadsDirectory), filePath)
+		if err != nil {
+			return err
+		}
+
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(fileInfo, "")
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(relativePath)
+
+		if entry.IsDir() {
+			header.Name += "/"
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		mediaFile, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer mediaFile.Close()
+
+		_, err = io.Copy(tarWriter, mediaFile)
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+// ImportMedia Restores the uploads from a .tar.gz file created with ExportMedia into the site's uploads directory,
+// replacing any files with the same path and keeping the rest
+func (s *Site) ImportMedia(file string) error {
+
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(s.StaticConfig.WorkingDirectory, file)
+	}
+
+	if s.Settings.Uploads.ReadOnly {
+		return fmt.Errorf("the site's shared uploads folder is read-only. Please set uploads.readOnly to false to import media")
+	}
+
+	archiveFile, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer archiveFile.Close()
+
+	fileInfo, err := archiveFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	uploadsDirectory := s.getUploadsDirectory()
+
+	err = os.MkdirAll(uploadsDirectory, 0755)
+	if err != nil {
+		return err
+	}
+
+	// Media is already compressed so the archive is close to the size of the files it holds
+	err = checkDiskSpace(uploadsDirectory, uint64(fileInfo.Size()))
+	if err != nil {
+		return err
+	}
+
+	gzipReader, err := gzip.NewReader(archiveFile)
+	if err != nil {
+		return fmt.Errorf("unable to read %s. Please use a .tar.gz file created with 'kana media export': %s", file, err)
+	}
+	defer gzipReader.Close()
+
+	return extractMediaArchive(tar.NewReader(gzipReader), uploadsDirectory)
+}
+
+// extractMediaArchive Writes the directories and files under "uploads/" in the archive to the uploads directory
+func extractMediaArchive(tarReader *tar.Reader, uploadsDirectory string) error {
+
+	for {
+
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		relativePath := strings.TrimPrefix(path.Clean(header.Name), "uploads")
+
+		// Skip anything outside of the uploads so an archive can't write to the rest of the site
+		if path.Clean(header.Name) != "uploads" && !strings.HasPrefix(relativePath, "/") {
+			continue
+		}
+
+		targetPath := filepath.Join(uploadsDirectory, filepath.FromSlash(relativePath))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(targetPath, 0755)
+		case tar.TypeReg:
+			err = writeMediaFile(tarReader, targetPath, header.FileInfo().Mode().Perm())
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeMediaFile Copies a file from the archive to the given path, creating its directory if needed
+func writeMediaFile(reader io.Reader, targetPath string, mode fs.FileMode) error {
+
+	err := os.MkdirAll(filepath.Dir(targetPath), 0755)
+	if err != nil {
+		return err
+	}
+
+	mediaFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode|0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(mediaFile, reader)
+
+	closeErr := mediaFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// RegenerateMedia Regenerates the thumbnails of every image in the media library, or only the sizes each is missing,
+// showing the progress wp-cli reports. Returns wp-cli's summary of what was regenerated.
+func (s *Site) RegenerateMedia(onlyMissing bool) (string, error) {
+
+	// Every thumbnail would fail to save one by one
+	if s.Settings.Uploads.ReadOnly {
+		return "", fmt.Errorf("the site's shared uploads folder is read-only. Please set uploads.readOnly to false to regenerate thumbnails")
+	}
+
+	command := []string{"media", "regenerate", "--yes"}
+
+	if onlyMissing {
+		command = append(command, "--only-missing")
+	}
+
+	spinner := console.StartSpinner("Regenerating thumbnails...")
+	defer spinner.Stop()
+
+	writer := &regenerateWriter{spinner: spinner}
+
+	statusCode, err := s.runWPCliAndFollow(command, writer)
+	if err != nil {
+		return "", err
+	}
+
+	writer.Flush()
+
+	if statusCode != 0 {
+		if len(writer.errors) > 0 {
+			return "", fmt.Errorf("unable to regenerate the thumbnails: %s", strings.Join(writer.errors, " "))
+		}
+
+		return "", fmt.Errorf("unable to regenerate the thumbnails. wp-cli exited with code %d", statusCode)
+	}
+
+	return writer.summary, nil
+}
+
+// Write Handles each complete line of output, keeping the rest until more is written
+func (w *regenerateWriter) Write(p []byte) (int, error) {
+
+	w.buffer = append(w.buffer, p...)
+
+	for {
+		end := bytes.IndexByte(w.buffer, '\n')
+		if end < 0 {
+			break
+		}
+
+		w.handleLine(string(w.buffer[:end]))
+		w.buffer = w.buffer[end+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush Handles any output left after the last line break
+func (w *regenerateWriter) Flush() {
+
+	if len(w.buffer) > 0 {
+		w.handleLine(string(w.buffer))
+		w.buffer = nil
+	}
+}
+
+// handleLine Shows the progress of a line of wp-cli output, passing on its warnings and keeping its errors and summary
+func (w *regenerateWriter) handleLine(line string) {
+
+	line = strings.TrimSpace(terminalColor.ReplaceAllString(line, ""))
+
+	switch {
+	case len(line) == 0:
+		return
+	case strings.HasPrefix(line, "Warning: "):
+		console.Warn("%s", strings.TrimPrefix(line, "Warning: "))
+	case strings.HasPrefix(line, "Error: "):
+		w.errors = append(w.errors, strings.TrimPrefix(line, "Error: "))
+	case strings.HasPrefix(line, "Success: "):
+		w.summary = strings.TrimPrefix(line, "Success: ")
+	default:
+		progress := regenerateProgress.FindStringSubmatch(line)
+		if progress == nil {
+			console.Debug("%s", line)
+			return
+		}
+
+		w.spinner.SetMessage("Regenerating thumbnails (%s of %s)...", progress[1], progress[2])
+
+		// The spinner is only drawn in a terminal so elsewhere each image is logged instead
+		if !console.IsInteractive() {
+			console.Info("%s", line)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package site
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+	"github.com/ChrisWiegman/kana-cli/internal/docker"
+
+	"github.com/spf13/viper"
+)
+
+type SiteInfo struct {
+	Name       string          `json:"name"`
+	Folder     string          `json:"folder"`
+	Running    bool            `json:"running"`
+	Status     string          `json:"status"`
+	URL        string          `json:"url"`
+	Type       string          `json:"type"`
+	PHP        string          `json:"php"`
+	Containers []ContainerInfo `json:"containers"`
+}
+
+type ContainerInfo struct {
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+}
+
+type OrphanedDirectory struct {
+	Name      string `json:"name"`
+	Directory string `json:"directory"`
+	Reason    string `json:"reason"`
+}
+
+type OrphanedContainers struct {
+	Name       string   `json:"name"`
+	Containers []string `json:"containers"`
+}
+
+// MovedSite Is a site whose linked folder no longer exists, which "kana relink" can repair so it is never pruned
+type MovedSite struct {
+	Name      string `json:"name"`
+	Directory string `json:"directory"`
+	Link      string `json:"link"`
+}
+
+type Orphans struct {
+	Directories []OrphanedDirectory  `json:"directories"`
+	Containers  []OrphanedContainers `json:"containers"`
+	Moved       []MovedSite          `json:"moved"`
+}
+
+// GetSites Returns every site with a directory in the app's sites directory along with the folder it is linked to
+func GetSites(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Viper) ([]SiteInfo, error) {
+
+	sites := []SiteInfo{}
+
+	siteNames, err := getSiteDirectoryNames(staticConfig)
+	if err != nil {
+		return sites, err
+	}
+
+	dockerClient, err := docker.NewController()
+	if err != nil {
+		return sites, err
+	}
+
+	for _, siteName := range siteNames {
+		sites = append(sites, getSiteInfo(staticConfig, dynamicConfig, dockerClient, siteName))
+	}
+
+	return sites, nil
+}
+
+// getSiteInfo Returns the details of the named site from the config in the folder it is linked to. Unlike LoadSite
+// nothing is written to the site's directory so sites can be listed without changing them.
+func getSiteInfo(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Viper, dockerClient *docker.DockerClient, siteName string) SiteInfo {
+
+	site := &Site{
+		dockerClient:  dockerClient,
+		StaticConfig:  staticConfig,
+		DynamicConfig: dynamicConfig,
+	}
+
+	site.setSiteName(siteName)
+
+	siteInfo := SiteInfo{
+		Name:       siteName,
+		Folder:     readSiteLink(site.StaticConfig.SiteDirectory),
+		Status:     "stopped",
+		URL:        site.GetURL(false),
+		Containers: []ContainerInfo{},
+	}
+
+	site.StaticConfig.WorkingDirectory = site.StaticConfig.SiteDirectory
+
+	if len(siteInfo.Folder) > 0 {
+		site.StaticConfig.WorkingDirectory = siteInfo.Folder
+	}
+
+	siteConfig, err := getSiteConfig(site.StaticConfig, dynamicConfig)
+	if err == nil {
+		site.SiteConfig = siteConfig
+		err = site.loadSettings()
+	}
+
+	// A site with a broken .kana.json is still listed with the details that don't depend on it
+	if err == nil {
+		siteInfo.URL = site.GetURL(false)
+		siteInfo.Type = site.Settings.Type
+		siteInfo.PHP = site.getRunningPHPVersion()
+	}
+
+	runningContainers := 0
+
+	for _, container := range site.GetSiteContainers() {
+
+		_, isRunning := dockerClient.IsContainerRunning(container)
+		if isRunning {
+			runningContainers++
+		}
+
+		siteInfo.Containers = append(siteInfo.Containers, ContainerInfo{
+			Name:    strings.TrimPrefix(container, site.getContainerName("")),
+			Running: isRunning,
+		})
+	}
+
+	_, siteInfo.Running = dockerClient.IsContainerRunning(site.getContainerName("wordpress"))
+
+	if runningContainers == len(siteInfo.Containers) {
+		siteInfo.Status = "running"
+	} else if runningContainers > 0 {
+		siteInfo.Status = "partial"
+	}
+
+	return siteInfo
+}
+
+// GetOrphans Returns the site directories that can't be used anymore and the containers of sites that have no directory.
+// A directory is orphaned if it has neither a database nor any containers. Sites whose linked folder is gone still have
+// their data so they are returned as moved instead.
+func GetOrphans(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Viper) (Orphans, error) {
+
+	orphans := Orphans{
+		Directories: []OrphanedDirectory{},
+		Containers:  []OrphanedContainers{},
+		Moved:       []MovedSite{},
+	}
+
+	siteNames, err := getSiteDirectoryNames(staticConfig)
+	if err != nil {
+		return orphans, err
+	}
+
+	dockerClient, err := docker.NewController()
+	if err != nil {
+		return orphans, err
+	}
+
+	siteLabel := appConfig.GetSiteLabel(dynamicConfig)
+
+	for _, siteName := range siteNames {
+
+		siteDirectory := path.Join(staticConfig.AppDirectory, "sites", siteName)
+
+		containers, err := dockerClient.ListContainers(siteLabel, siteName)
+		if err != nil {
+			return orphans, err
+		}
+
+		if _, err := os.Stat(path.Join(siteDirectory, "database")); os.IsNotExist(err) && len(containers) == 0 {
+			orphans.Directories = append(orphans.Directories, OrphanedDirectory{
+				Name:      siteName,
+				Directory: siteDirectory,
+				Reason:    "it has no database or containers",
+			})
+
+			continue
+		}
+
+		// The folder may only have been moved so the site is kept for "kana relink"
+		if link := readSiteLink(siteDirectory); len(link) > 0 {
+			if _, err := os.Stat(link); os.IsNotExist(err) {
+				orphans.Moved = append(orphans.Moved, MovedSite{
+					Name:      siteName,
+					Directory: siteDirectory,
+					Link:      link,
+				})
+			}
+		}
+	}
+
+	containerSites, err := dockerClient.ListContainerLabelValues(siteLabel)
+	if err != nil {
+		return orphans, err
+	}
+
+	sort.Strings(containerSites)
+
+	for _, containerSite := range containerSites {
+
+		if appConfig.CheckString(containerSite, siteNames) {
+			continue
+		}
+
+		containers, err := dockerClient.ListContainers(siteLabel, containerSite)
+		if err != nil {
+			return orphans, err
+		}
+
+		orphans.Containers = append(orphans.Containers, OrphanedContainers{
+			Name:       containerSite,
+			Containers: containers,
+		})
+	}
+
+	return orphans, nil
+}
+
+// PruneOrphans Removes the given orphaned containers and site directories. Moved sites are left alone.
+func PruneOrphans(orphans Orphans) error {
+
+	dockerClient, err := docker.NewController()
+	if err != nil {
+		return err
+	}
+
+	for _, orphanedContainers := range orphans.Containers {
+		for _, container := range orphanedContainers.Containers {
+
+			err = dockerClient.ContainerRemove(container)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, orphanedDirectory := range orphans.Directories {
+
+		err = os.RemoveAll(orphanedDirectory.Directory)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PruneCLIImages Removes the wp-cli images of PHP versions no site uses anymore, which are left behind when sites
+// change their PHP version. Nothing is removed if the PHP version of any site can't be read as its image might be
+// removed. Returns the images that were removed.
+func PruneCLIImages(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Viper) ([]string, error) {
+
+	removedImages := []string{}
+
+	sites, err := GetSites(staticConfig, dynamicConfig)
+	if err != nil {
+		return removedImages, err
+	}
+
+	dockerClient, err := docker.NewController()
+	if err != nil {
+		return removedImages, err
+	}
+
+	usedImages := []string{appConfig.GetImage(dynamicConfig, "cli", dynamicConfig.GetString("php"))}
+
+	for _, siteInfo := range sites {
+
+		if len(siteInfo.PHP) == 0 {
+			console.Warn("Unable to read the PHP version of %s so no wp-cli images were removed. Run 'kana config validate --name %s' to check its config", siteInfo.Name, siteInfo.Name)
+			return removedImages, nil
+		}
+
+		usedImages = append(usedImages, appConfig.GetImage(dynamicConfig, "cli", siteInfo.PHP))
+	}
+
+	for _, phpVersion := range appConfig.ValidPHPVersions {
+
+		image := appConfig.GetImage(dynamicConfig, "cli", phpVersion)
+
+		if appConfig.CheckString(image, usedImages) || appConfig.CheckString(image, removedImages) {
+			continue
+		}
+
+		removed, err := dockerClient.RemoveImage(image)
+		if err != nil {
+			return removedImages, err
+		}
+
+		if removed {
+			removedImages = append(removedImages, image)
+		}
+	}
+
+	return removedImages, nil
+}
+
+// getSiteDirectoryNames Returns the names of the directories in the app's sites directory
+func getSiteDirectoryNames(staticConfig appConfig.StaticConfig) ([]string, error) {
+
+	siteNames := []string{}
+
+	siteDirectories, err := os.ReadDir(path.Join(staticConfig.AppDirectory, "sites"))
+	if err != nil && !os.IsNotExist(err) {
+		return siteNames, err
+	}
+
+	for _, siteDirectory := range siteDirectories {
+		if siteDirectory.IsDir() {
+			siteNames = append(siteNames, siteDirectory.Name())
+		}
+	}
+
+	return siteNames, nil
+}
+
+// readSiteLink Returns the folder a site directory is linked to without creating the link like loadSiteLink does
+func readSiteLink(siteDirectory string) string {
+
+	contents, err := os.ReadFile(path.Join(siteDirectory, "link.json"))
+	if err != nil {
+		return ""
+	}
+
+	siteLink := struct {
+		Link string `json:"link"`
+	}{}
+
+	if json.Unmarshal(contents, &siteLink) != nil {
+		return ""
+	}
+
+	return siteLink.Link
+}
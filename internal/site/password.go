@@ -0,0 +1,57 @@
+package site
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const passwordCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+const passwordLength = 24
+
+// ResetPassword Sets the password of the given WordPress user, generating a random one if the password is empty, and returns it
+func (s *Site) ResetPassword(user, password string) (string, error) {
+
+	var err error
+
+	if len(password) == 0 {
+		password, err = generatePassword()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	statusCode, output, err := s.runWPCli([]string{"user", "update", user, fmt.Sprintf("--user_pass=%s", password), "--skip-email"}, []mount.Mount{})
+	if err != nil {
+		return "", err
+	}
+
+	if statusCode != 0 {
+		return "", fmt.Errorf("unable to reset the password of %s: %s", user, strings.TrimSpace(output))
+	}
+
+	return password, nil
+}
+
+// generatePassword Returns a random password made of letters and numbers
+func generatePassword() (string, error) {
+
+	password := make([]byte, passwordLength)
+	max := big.NewInt(int64(len(passwordCharacters)))
+
+	for i := range password {
+
+		index, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		password[i] = passwordCharacters[index.Int64()]
+	}
+
+	return string(password), nil
+}
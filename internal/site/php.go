@@ -0,0 +1,155 @@
+package site
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+)
+
+var validPHPExtension = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// InstallPHPExtensions Installs Xdebug, if the "xdebug" option is set, and the extensions in the "phpExtensions" option
+// in the site's WordPress container, restarting it once if anything was installed. Returns the extensions installed.
+func (s *Site) InstallPHPExtensions() ([]string, error) {
+
+	installed := []string{}
+
+	if s.Settings.Xdebug {
+
+		err := s.shareXdebugOutput()
+		if err != nil {
+			return installed, err
+		}
+
+		isInstalled, err := s.installXdebug()
+		if err != nil {
+			return installed, err
+		}
+
+		if isInstalled {
+			installed = append(installed, "xdebug")
+		}
+	}
+
+	for _, extension := range s.Settings.PHPExtensions {
+
+		isInstalled, err := s.installPHPExtension(extension)
+		if err != nil {
+			return installed, err
+		}
+
+		if isInstalled {
+			installed = append(installed, extension)
+		}
+	}
+
+	if len(installed) == 0 {
+		return installed, nil
+	}
+
+	return installed, s.restartWordPress()
+}
+
+// installXdebug Installs Xdebug and its settings in the site's WordPress container without restarting it.
+// Returns false if Xdebug was already installed.
+func (s *Site) installXdebug() (bool, error) {
+
+	isInstalled, err := s.installPHPExtension("xdebug")
+	if err != nil || !isInstalled {
+		return false, err
+	}
+
+	settings := []string{
+		"xdebug.start_with_request=yes",
+		"xdebug.mode=debug",
+		"xdebug.client_host=host.docker.internal",
+		"xdebug.discover_client_host=on",
+		"xdebug.start_with_request=trigger",
+	}
+
+	for _, setting := range settings {
+
+		_, err = s.runCli(fmt.Sprintf("echo '%s' >> /usr/local/etc/php/php.ini", setting), false)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, s.writeXdebugMode()
+}
+
+// installPHPExtension Installs and enables a PHP extension in the site's WordPress container without restarting it.
+// Extensions bundled with PHP are built from its source and others are installed from PECL.
+// Returns false if the extension was already active.
+func (s *Site) installPHPExtension(extension string) (bool, error) {
+
+	// The name is used in a shell command so only allow the characters extension names are made of
+	if !validPHPExtension.MatchString(extension) {
+		return false, fmt.Errorf("invalid PHP extension %q. Please use the extension's name, such as \"bcmath\"", extension)
+	}
+
+	active, err := s.getActivePHPExtensions()
+	if err != nil {
+		return false, err
+	}
+
+	if activeExtension(extension, active) {
+		return false, nil
+	}
+
+	console.Info("Installing the %s PHP extension...", extension)
+
+	installCommand := fmt.Sprintf(
+		"docker-php-source extract && if [ -d /usr/src/php/ext/%[1]s ]; then docker-php-ext-install %[1]s; else pecl install %[1]s && docker-php-ext-enable %[1]s; fi",
+		extension)
+
+	output, err := s.runCli(installCommand, false)
+	if err != nil {
+		return false, err
+	}
+
+	if output.ExitCode != 0 {
+		return false, fmt.Errorf("unable to install the %s PHP extension: %s", extension, strings.TrimSpace(output.StdErr))
+	}
+
+	return true, nil
+}
+
+// getActivePHPExtensions Returns the lowercase names of the extensions loaded by PHP in the site's WordPress container
+func (s *Site) getActivePHPExtensions() ([]string, error) {
+
+	output, err := s.runCli("php -m", false)
+	if err != nil {
+		return []string{}, err
+	}
+
+	extensions := []string{}
+
+	for _, line := range strings.Split(output.StdOut, "\n") {
+
+		line = strings.ToLower(strings.TrimSpace(line))
+
+		// Skip the section headers such as "[PHP Modules]"
+		if len(line) == 0 || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		extensions = append(extensions, line)
+	}
+
+	return extensions, nil
+}
+
+// activeExtension Returns true if the extension is in the list of active extensions, which names Zend extensions in full
+func activeExtension(extension string, active []string) bool {
+
+	for _, activeName := range active {
+		if activeName == extension || strings.ReplaceAll(activeName, " ", "") == fmt.Sprintf("zend%s", extension) {
+			return true
+		}
+	}
+
+	return false
+}
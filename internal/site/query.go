@@ -0,0 +1,184 @@
+package site
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+var readStatement = regexp.MustCompile(`(?i)^\s*(select|show|describe|desc|explain|with)\b`)
+
+const affectedRowsQuery = "SELECT ROW_COUNT() AS affected_rows"
+
+type QueryResult struct {
+	Statement    string     `json:"statement"`
+	Columns      []string   `json:"columns,omitempty"`
+	Rows         [][]string `json:"rows,omitempty"`
+	AffectedRows int64      `json:"affectedRows"`
+	IsWrite      bool       `json:"isWrite"`
+}
+
+type xmlResultSet struct {
+	Statement string `xml:"statement,attr"`
+	Rows      []struct {
+		Fields []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"field"`
+	} `xml:"row"`
+}
+
+// QueryDatabase Runs one or more SQL statements against the site's database, returning the rows of each read
+// and the number of rows changed by each write
+func (s *Site) QueryDatabase(sql string) ([]QueryResult, error) {
+
+	statements := splitSQLStatements(sql)
+	if len(statements) == 0 {
+		return []QueryResult{}, fmt.Errorf("please enter a SQL query to run")
+	}
+
+	// Writes don't return anything so follow each with a query for the rows it changed
+	script := ""
+
+	for _, statement := range statements {
+
+		script += statement + ";\n"
+
+		if !readStatement.MatchString(statement) {
+			script += affectedRowsQuery + ";\n"
+		}
+	}
+
+	statusCode, output, err := s.runWPCli([]string{"db", "query", script, "--xml"}, []mount.Mount{})
+	if err != nil {
+		return []QueryResult{}, err
+	}
+
+	if statusCode != 0 {
+		return []QueryResult{}, fmt.Errorf("unable to run the query: %s", strings.TrimSpace(output))
+	}
+
+	return parseQueryResults(statements, output)
+}
+
+// parseQueryResults Matches the XML result sets from the mysql client to the statements that produced them
+func parseQueryResults(statements []string, output string) ([]QueryResult, error) {
+
+	results := []QueryResult{}
+	decoder := xml.NewDecoder(strings.NewReader(output))
+
+	for _, statement := range statements {
+
+		resultSet, err := nextResultSet(decoder)
+		if err != nil {
+			return results, err
+		}
+
+		result := QueryResult{
+			Statement: statement,
+			Columns:   []string{},
+			Rows:      [][]string{},
+		}
+
+		for _, row := range resultSet.Rows {
+
+			values := []string{}
+
+			for i, field := range row.Fields {
+
+				if len(result.Rows) == 0 && len(result.Columns) == i {
+					result.Columns = append(result.Columns, field.Name)
+				}
+
+				values = append(values, field.Value)
+			}
+
+			result.Rows = append(result.Rows, values)
+		}
+
+		if !readStatement.MatchString(statement) {
+
+			countSet, err := nextResultSet(decoder)
+			if err != nil {
+				return results, err
+			}
+
+			result.IsWrite = true
+
+			if len(countSet.Rows) > 0 && len(countSet.Rows[0].Fields) > 0 {
+				result.AffectedRows, _ = strconv.ParseInt(countSet.Rows[0].Fields[0].Value, 10, 64)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// nextResultSet Decodes the next result set in the mysql client's XML output
+func nextResultSet(decoder *xml.Decoder) (xmlResultSet, error) {
+
+	var resultSet xmlResultSet
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return resultSet, fmt.Errorf("the database returned fewer results than expected")
+		}
+
+		if err != nil {
+			return resultSet, err
+		}
+
+		if element, ok := token.(xml.StartElement); ok && element.Name.Local == "resultset" {
+			return resultSet, decoder.DecodeElement(&resultSet, &element)
+		}
+	}
+}
+
+// splitSQLStatements Splits a SQL script on the semicolons between statements, ignoring any inside quotes
+func splitSQLStatements(sql string) []string {
+
+	statements := []string{}
+	current := strings.Builder{}
+	var quote rune
+	escaped := false
+
+	for _, char := range sql {
+
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\' && quote != 0:
+			escaped = true
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"' || char == '`':
+			quote = char
+		case char == ';':
+			if statement := strings.TrimSpace(current.String()); len(statement) > 0 {
+				statements = append(statements, statement)
+			}
+
+			current.Reset()
+
+			continue
+		}
+
+		current.WriteRune(char)
+	}
+
+	if statement := strings.TrimSpace(current.String()); len(statement) > 0 {
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
@@ -0,0 +1,107 @@
+package site
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// scaffoldDirectory Is where the site's folder is mounted in the wp-cli container so scaffolded plugins land on the host
+const scaffoldDirectory = "/var/www/scaffold"
+
+// ScaffoldComponents Holds the wp scaffold commands that add to an existing plugin or theme
+var ScaffoldComponents = []string{
+	"block",
+	"post-type",
+	"taxonomy",
+}
+
+// ScaffoldPlugin Creates a new plugin in the site's folder using wp scaffold plugin with the given arguments
+func (s *Site) ScaffoldPlugin(args []string) (string, error) {
+
+	command := append([]string{"scaffold", "plugin"}, args...)
+
+	if !hasFlag(args, "--dir") {
+		command = append(command, fmt.Sprintf("--dir=%s", scaffoldDirectory))
+	}
+
+	return s.runScaffold(command, []mount.Mount{
+		{
+			Type:   mount.TypeBind,
+			Source: s.StaticConfig.WorkingDirectory,
+			Target: scaffoldDirectory,
+		},
+	})
+}
+
+// ScaffoldTheme Creates a new theme in the site's folder using wp scaffold _s, or wp scaffold child-theme if a parent theme is given
+func (s *Site) ScaffoldTheme(args []string) (string, error) {
+
+	scaffold := "_s"
+
+	if hasFlag(args, "--parent_theme") {
+		scaffold = "child-theme"
+	}
+
+	// Theme scaffolds always write to the themes folder so the site's folder takes its place for this command.
+	// Skipping themes stops WordPress from trying to load the active theme that is now hidden.
+	command := append([]string{"scaffold", scaffold}, args...)
+	command = append(command, "--skip-themes")
+
+	return s.runScaffold(command, []mount.Mount{
+		{
+			Type:   mount.TypeBind,
+			Source: s.StaticConfig.WorkingDirectory,
+			Target: path.Join(s.getWordPressPath(), "wp-content", "themes"),
+		},
+	})
+}
+
+// ScaffoldComponent Adds a block, post type or taxonomy to a plugin or theme. Plugin and theme sites use their own code unless
+// the arguments name another plugin or theme.
+func (s *Site) ScaffoldComponent(component string, args []string) (string, error) {
+
+	if !appConfig.CheckString(component, ScaffoldComponents) {
+		return "", fmt.Errorf("unable to scaffold %q. Please choose one of %s", component, strings.Join(ScaffoldComponents, ", "))
+	}
+
+	command := append([]string{"scaffold", component}, args...)
+	siteType := s.Settings.Type
+
+	if !hasFlag(args, "--plugin") && !hasFlag(args, "--theme") && (siteType == "plugin" || siteType == "theme") {
+		command = append(command, fmt.Sprintf("--%s=%s", siteType, s.StaticConfig.SiteName))
+	}
+
+	return s.runScaffold(command, []mount.Mount{})
+}
+
+// runScaffold Runs a wp scaffold command returning its output
+func (s *Site) runScaffold(command []string, extraMounts []mount.Mount) (string, error) {
+
+	statusCode, output, err := s.runWPCli(command, extraMounts)
+	if err != nil {
+		return "", err
+	}
+
+	if statusCode != 0 {
+		return "", fmt.Errorf("unable to scaffold the %s: %s", command[1], strings.TrimSpace(output))
+	}
+
+	return output, nil
+}
+
+// hasFlag Returns true if the arguments include the flag, either on its own or with a value
+func hasFlag(args []string, flag string) bool {
+
+	for _, arg := range args {
+		if arg == flag || strings.HasPrefix(arg, flag+"=") {
+			return true
+		}
+	}
+
+	return false
+}
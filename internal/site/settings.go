@@ -0,0 +1,193 @@
+package site
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+
+	"github.com/spf13/viper"
+)
+
+// SiteSettings Are the site's options from its .kana.json file and the app config, decoded into typed fields so a
+// mistyped key is caught by the compiler instead of silently returning an empty value
+type SiteSettings struct {
+	Name             string            `mapstructure:"name"`
+	PHP              string            `mapstructure:"php"`
+	PHPVersions      []string          `mapstructure:"phpVersions"`
+	PHPExtensions    []string          `mapstructure:"phpExtensions"`
+	Type             string            `mapstructure:"type"`
+	Local            bool              `mapstructure:"local"`
+	Xdebug           bool              `mapstructure:"xdebug"`
+	XdebugMode       string            `mapstructure:"xdebugMode"`
+	XdebugOutputDir  string            `mapstructure:"xdebugOutputDir"`
+	Insecure         bool              `mapstructure:"insecure"`
+	KeepConfig       bool              `mapstructure:"keepConfig"`
+	SkipPlugins      bool              `mapstructure:"skipPlugins"`
+	Gitignore        bool              `mapstructure:"gitignore"`
+	Hosts            bool              `mapstructure:"hosts"`
+	HostUser         bool              `mapstructure:"hostUser"`
+	RestartMode      string            `mapstructure:"restartMode"`
+	WordPressVersion string            `mapstructure:"wordpressVersion"`
+	TablePrefix      string            `mapstructure:"tablePrefix"`
+	AnonymizeFields  []string          `mapstructure:"anonymizeFields"`
+	Aliases          AliasSettings     `mapstructure:"aliases"`
+	Labels           map[string]string `mapstructure:"labels"`
+	Command          []string          `mapstructure:"command"`
+	Entrypoint       string            `mapstructure:"entrypoint"`
+	Traefik          TraefikSettings   `mapstructure:"traefik"`
+	Plugins          []string          `mapstructure:"plugins"`
+	Themes           []string          `mapstructure:"themes"`
+	ActiveTheme      string            `mapstructure:"activeTheme"`
+	Languages        []string          `mapstructure:"languages"`
+	Language         string            `mapstructure:"language"`
+	Users            []SiteUser        `mapstructure:"users"`
+	Database         DatabaseSettings  `mapstructure:"database"`
+	Subdirectory     string            `mapstructure:"subdirectory"`
+	Directories      []string          `mapstructure:"directories"`
+	MUPlugins        string            `mapstructure:"muPlugins"`
+	DemoContent      bool              `mapstructure:"demoContent"`
+	DemoContentFile  string            `mapstructure:"demoContentFile"`
+	Uploads          UploadsSettings   `mapstructure:"uploads"`
+	VerifyRestAPI    bool              `mapstructure:"verifyRestAPI"`
+	HealthPath       string            `mapstructure:"healthPath"`
+	HealthStatus     int               `mapstructure:"healthStatus"`
+	Dockerfile       string            `mapstructure:"dockerfile"`
+	Timezone         string            `mapstructure:"timezone"`
+	DateFormat       string            `mapstructure:"dateFormat"`
+	TimeFormat       string            `mapstructure:"timeFormat"`
+}
+
+type AliasSettings struct {
+	Database  []string `mapstructure:"database"`
+	WordPress []string `mapstructure:"wordpress"`
+}
+
+type TraefikSettings struct {
+	Priority    int      `mapstructure:"priority"`
+	Middlewares []string `mapstructure:"middlewares"`
+	BasicAuth   []string `mapstructure:"basicAuth"`
+}
+
+type UploadsSettings struct {
+	Directory string `mapstructure:"directory"`
+	ReadOnly  bool   `mapstructure:"readOnly"`
+}
+
+type DatabaseSettings struct {
+	Seed       []string `mapstructure:"seed"`
+	SeedAlways bool     `mapstructure:"seedAlways"`
+	Expose     bool     `mapstructure:"expose"`
+	Port       int      `mapstructure:"port"`
+	Ephemeral  bool     `mapstructure:"ephemeral"`
+}
+
+// loadSettings Decodes the site config into the typed settings. An option with a value of the wrong type is replaced with
+// its default and a warning so a typo in .kana.json can't keep every command, including 'kana config validate',
+// from running.
+func (s *Site) loadSettings() error {
+
+	settings, err := s.decodeSettings()
+	if err != nil {
+		return err
+	}
+
+	settings.Labels = s.getCustomLabels(settings.Labels)
+	s.Settings = settings
+
+	return nil
+}
+
+// decodeSettings Returns the typed settings from the site config, leaving any option that can't be decoded at its default
+func (s *Site) decodeSettings() (SiteSettings, error) {
+
+	settings := SiteSettings{}
+
+	err := s.SiteConfig.Unmarshal(&settings)
+	if err == nil {
+		return settings, nil
+	}
+
+	// Decode the options one at a time to find the ones that can't be used and leave them at their defaults
+	validConfig := viper.New()
+	setSiteConfigDefaults(validConfig, s.DynamicConfig)
+
+	for _, key := range s.SiteConfig.AllKeys() {
+
+		keyConfig := viper.New()
+		keyConfig.Set(key, s.SiteConfig.Get(key))
+
+		err = keyConfig.Unmarshal(&SiteSettings{})
+		if err != nil {
+			console.Warn("Invalid value for %s in .kana.json. Using the default instead. Run 'kana config validate' for details.", key)
+			continue
+		}
+
+		validConfig.Set(key, s.SiteConfig.Get(key))
+	}
+
+	settings = SiteSettings{}
+
+	err = validConfig.Unmarshal(&settings)
+
+	return settings, err
+}
+
+// getCustomLabels Returns the labels from the site's "labels" option that aren't reserved for Kana. Viper lowercases the
+// keys of maps so the labels are read from .kana.json itself when they can be, keeping labels such as com.Example.Team intact.
+func (s *Site) getCustomLabels(decodedLabels map[string]string) map[string]string {
+
+	labels := map[string]string{}
+
+	if rawLabels, ok := readRawLabels(s.SiteConfig.ConfigFileUsed()); ok {
+		decodedLabels = rawLabels
+	}
+
+	for label, value := range decodedLabels {
+		if !isReservedLabel(label, s.DynamicConfig) {
+			labels[label] = value
+		}
+	}
+
+	return labels
+}
+
+// readRawLabels Returns the "labels" option exactly as it is written in the config file, or false if it can't be read
+func readRawLabels(configFile string) (map[string]string, bool) {
+
+	if len(configFile) == 0 {
+		return nil, false
+	}
+
+	contents, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, false
+	}
+
+	rawConfig := struct {
+		Labels map[string]string `json:"labels"`
+	}{}
+
+	err = json.Unmarshal(contents, &rawConfig)
+	if err != nil || rawConfig.Labels == nil {
+		return nil, false
+	}
+
+	return rawConfig.Labels, true
+}
+
+// isReservedLabel Returns true if the label is one Kana or Traefik uses, which a site can't set
+func isReservedLabel(label string, dynamicConfig *viper.Viper) bool {
+	return strings.HasPrefix(label, appConfig.GetPrefix(dynamicConfig)+".") || strings.HasPrefix(label, "traefik.")
+}
+
+// setSetting Changes an option in the site config, which is saved by writeSiteConfig, and updates the typed settings
+func (s *Site) setSetting(key string, value interface{}) {
+
+	s.SiteConfig.Set(key, value)
+
+	// Values set by Kana always have the right type and the rest of the config was decoded when the site was loaded
+	s.loadSettings()
+}
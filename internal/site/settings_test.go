@@ -0,0 +1,41 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestGetCustomLabels(t *testing.T) {
+
+	configFile := filepath.Join(t.TempDir(), ".kana.json")
+
+	err := os.WriteFile(configFile, []byte(`{"labels": {"com.Example.Team": "Web", "kana.site": "other", "traefik.enable": "false"}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	siteConfig := viper.New()
+	siteConfig.SetConfigFile(configFile)
+
+	err = siteConfig.ReadInConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dynamicConfig := viper.New()
+	dynamicConfig.Set("prefix", "kana")
+
+	site := &Site{
+		SiteConfig:    siteConfig,
+		DynamicConfig: dynamicConfig,
+	}
+
+	labels := site.getCustomLabels(siteConfig.GetStringMapString("labels"))
+
+	if len(labels) != 1 || labels["com.Example.Team"] != "Web" {
+		t.Errorf("getCustomLabels() returned %v. Expected only com.Example.Team with its case kept", labels)
+	}
+}
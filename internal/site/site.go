@@ -1,19 +1,24 @@
 package site
 
 import (
-	"crypto/tls"
-	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 	"github.com/ChrisWiegman/kana-cli/internal/docker"
+	"github.com/ChrisWiegman/kana-cli/internal/traefik"
 
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
@@ -25,6 +30,7 @@ type Site struct {
 	StaticConfig  appConfig.StaticConfig
 	DynamicConfig *viper.Viper
 	SiteConfig    *viper.Viper
+	Settings      SiteSettings
 	rootCert      string
 	siteDomain    string
 	secureURL     string
@@ -52,20 +58,50 @@ func NewSite(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Viper) (*
 		return site, err
 	}
 
+	err = site.loadSettings()
+	if err != nil {
+		return site, err
+	}
+
 	// Setup other options generated from config items
 	site.rootCert = path.Join(staticConfig.AppDirectory, "certs", staticConfig.RootCert)
-	site.siteDomain = fmt.Sprintf("%s.%s", staticConfig.SiteName, staticConfig.AppDomain)
-	site.secureURL = fmt.Sprintf("https://%s/", site.siteDomain)
-	site.url = fmt.Sprintf("http://%s/", site.siteDomain)
+	site.setSiteName(staticConfig.SiteName)
+
+	// A site renamed with "kana rename" saves its new name in the local .kana.json file
+	if len(site.Settings.Name) > 0 {
+		site.setSiteName(appConfig.SanitizeSiteName(site.Settings.Name))
+	}
 
 	return site, nil
 }
 
+// setSiteName Sets the site name and resets all variables derived from it
+func (s *Site) setSiteName(siteName string) {
+
+	s.StaticConfig.SiteName = siteName
+	s.StaticConfig.SiteDirectory = path.Join(s.StaticConfig.AppDirectory, "sites", siteName)
+
+	s.siteDomain = fmt.Sprintf("%s.%s", siteName, s.StaticConfig.AppDomain)
+	s.secureURL = fmt.Sprintf("https://%s/", s.siteDomain)
+	s.url = fmt.Sprintf("http://%s/", s.siteDomain)
+}
+
 // ProcessNameFlag Processes the name flag on the site resetting all appropriate site variables
 func (s *Site) ProcessNameFlag(cmd *cobra.Command) error {
 
 	// Don't run this on commands that wouldn't possibly use it.
-	if cmd.Use == "config" || cmd.Use == "version" || cmd.Use == "help" {
+	if cmd.Use == "config" || cmd.Use == "version" || cmd.Use == "help" || cmd.Use == "watch" || cmd.Use == "prune" {
+		return nil
+	}
+
+	// The proxy is shared by all sites
+	if cmd.HasParent() && cmd.Parent().Use == "proxy" {
+		return nil
+	}
+
+	// Commands run against all sites load each site themselves
+	allFlag := cmd.Flags().Lookup("all")
+	if allFlag != nil && allFlag.Changed {
 		return nil
 	}
 
@@ -82,16 +118,51 @@ func (s *Site) ProcessNameFlag(cmd *cobra.Command) error {
 			}
 		}
 
-		s.StaticConfig.SiteName = appConfig.SanitizeSiteName(cmd.Flags().Lookup("name").Value.String())
-		s.StaticConfig.SiteDirectory = (path.Join(s.StaticConfig.AppDirectory, "sites", s.StaticConfig.SiteName))
-
-		s.siteDomain = fmt.Sprintf("%s.%s", s.StaticConfig.SiteName, s.StaticConfig.AppDomain)
-		s.secureURL = fmt.Sprintf("https://%s/", s.siteDomain)
-		s.url = fmt.Sprintf("http://%s/", s.siteDomain)
+		s.setSiteName(appConfig.SanitizeSiteName(cmd.Flags().Lookup("name").Value.String()))
 
 		siteLink = s.StaticConfig.SiteDirectory
+
+		// Only start creates sites so every other command needs one that exists rather than an empty new directory
+		if cmd.Use != "start" && !s.siteExists() {
+			return fmt.Errorf("the site %s doesn't exist. Run 'kana list' to see your sites or 'kana start --name %s' to create it", s.StaticConfig.SiteName, s.StaticConfig.SiteName)
+		}
+	}
+
+	return s.loadSiteLink(siteLink)
+}
+
+// siteExists Returns true if the site has a directory or has containers, whether it is running or not
+func (s *Site) siteExists() bool {
+
+	if _, err := os.Stat(s.StaticConfig.SiteDirectory); err == nil {
+		return true
+	}
+
+	return s.IsSiteRunning()
+}
+
+// LoadSite Switches the site object to the existing site with the given name, reloading its config
+func (s *Site) LoadSite(siteName string) error {
+
+	s.setSiteName(siteName)
+
+	err := s.loadSiteLink(s.StaticConfig.SiteDirectory)
+	if err != nil {
+		return err
 	}
 
+	// The site's config lives in the folder it is linked to
+	s.SiteConfig, err = getSiteConfig(s.StaticConfig, s.DynamicConfig)
+	if err != nil {
+		return err
+	}
+
+	return s.loadSettings()
+}
+
+// loadSiteLink Reads the folder the site is linked to, creating the link with the given default if it doesn't exist yet
+func (s *Site) loadSiteLink(siteLink string) error {
+
 	siteLinkConfig := viper.New()
 
 	siteLinkConfig.SetDefault("link", siteLink)
@@ -117,51 +188,248 @@ func (s *Site) ProcessNameFlag(cmd *cobra.Command) error {
 
 	s.StaticConfig.WorkingDirectory = siteLinkConfig.GetString("link")
 
+	// Sites loaded from the current folder should be linked to it, unless the folder was moved or shares its name
+	if siteLink != s.StaticConfig.SiteDirectory && siteLink != s.StaticConfig.WorkingDirectory {
+		if _, err := os.Stat(s.StaticConfig.WorkingDirectory); os.IsNotExist(err) {
+			console.Warn("The site %s is linked to %s, which no longer exists. Run 'kana relink' to link it to the current folder", s.StaticConfig.SiteName, s.StaticConfig.WorkingDirectory)
+		} else {
+			console.Warn("The site %s is linked to %s rather than the current folder", s.StaticConfig.SiteName, s.StaticConfig.WorkingDirectory)
+		}
+	}
+
+	return nil
+}
+
+// writeSiteLink Links the site to the given folder, replacing any existing link
+func (s *Site) writeSiteLink(folder string) error {
+
+	siteLinkConfig := viper.New()
+	siteLinkConfig.Set("link", folder)
+
+	err := siteLinkConfig.WriteConfigAs(path.Join(s.StaticConfig.SiteDirectory, "link.json"))
+	if err != nil {
+		return err
+	}
+
+	s.StaticConfig.WorkingDirectory = folder
+
 	return nil
 }
 
-// GetURL returns the appropriate URL for the site
+// RelinkSite Links the site to the given folder, such as after the folder it was linked to has been moved, and reloads
+// the site's config from it. The folder must be one that would load this site.
+func (s *Site) RelinkSite(folder string) error {
+
+	if s.StaticConfig.WorkingDirectory == s.StaticConfig.SiteDirectory {
+		return fmt.Errorf("the site %s was created with --name so it isn't linked to a folder", s.StaticConfig.SiteName)
+	}
+
+	folder, err := filepath.Abs(folder)
+	if err != nil {
+		return err
+	}
+
+	folderInfo, err := os.Stat(folder)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("the folder %s doesn't exist", folder)
+		}
+
+		return err
+	}
+
+	if !folderInfo.IsDir() {
+		return fmt.Errorf("%s isn't a folder", folder)
+	}
+
+	if folder == s.StaticConfig.WorkingDirectory {
+		return fmt.Errorf("the site %s is already linked to %s", s.StaticConfig.SiteName, folder)
+	}
+
+	// Linking a folder that loads a different site would leave this one unreachable from it
+	folderSiteName, err := getFolderSiteName(folder)
+	if err != nil {
+		return err
+	}
+
+	if folderSiteName != s.StaticConfig.SiteName {
+		return fmt.Errorf("the folder %s belongs to the site %s rather than %s. Rename the folder or set the site's name in its .kana.json file", folder, folderSiteName, s.StaticConfig.SiteName)
+	}
+
+	err = s.writeSiteLink(folder)
+	if err != nil {
+		return err
+	}
+
+	s.SiteConfig, err = getSiteConfig(s.StaticConfig, s.DynamicConfig)
+	if err != nil {
+		return err
+	}
+
+	return s.loadSettings()
+}
+
+// getFolderSiteName Returns the name of the site Kana would load from the given folder
+func getFolderSiteName(folder string) (string, error) {
+
+	folderConfig := viper.New()
+	folderConfig.SetConfigFile(path.Join(folder, ".kana.json"))
+
+	err := folderConfig.ReadInConfig()
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("unable to read the .kana.json file in %s: %s", folder, err)
+	}
+
+	// A site renamed with "kana rename" saves its new name in the local .kana.json file
+	if name := folderConfig.GetString("name"); len(name) > 0 {
+		return appConfig.SanitizeSiteName(name), nil
+	}
+
+	return appConfig.SanitizeSiteName(filepath.Base(folder)), nil
+}
+
+// GetSiteNames Returns the names of all sites found in the sites directory or in Docker
+func GetSiteNames(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Viper) ([]string, error) {
+
+	siteNames, err := getSiteDirectoryNames(staticConfig)
+	if err != nil {
+		return siteNames, err
+	}
+
+	dockerClient, err := docker.NewController()
+	if err != nil {
+		return siteNames, err
+	}
+
+	// Sites may also have running containers without a folder if something was cleaned up by hand
+	runningSites, err := dockerClient.ListContainerLabelValues(appConfig.GetSiteLabel(dynamicConfig))
+	if err != nil {
+		return siteNames, err
+	}
+
+	for _, runningSite := range runningSites {
+		if !appConfig.CheckString(runningSite, siteNames) {
+			siteNames = append(siteNames, runningSite)
+		}
+	}
+
+	sort.Strings(siteNames)
+
+	return siteNames, nil
+}
+
+// GetURL returns the appropriate URL for the site, including the subdirectory WordPress is installed in. Sites running in insecure mode always use the http URL.
 func (s *Site) GetURL(insecure bool) string {
 
-	if insecure {
-		return s.url
+	siteURL := s.secureURL
+
+	if insecure || s.Settings.Insecure {
+		siteURL = s.url
 	}
 
-	return s.secureURL
+	if len(s.getSubdirectory()) > 0 {
+		siteURL = fmt.Sprintf("%s%s/", siteURL, s.getSubdirectory())
+	}
+
+	return siteURL
+}
+
+// getDomainURL Returns the URL of WordPress on the given domain using the same scheme as the site
+func (s *Site) getDomainURL(domain string) string {
+
+	scheme := "https"
+
+	if s.Settings.Insecure {
+		scheme = "http"
+	}
+
+	if len(s.getSubdirectory()) > 0 {
+		return fmt.Sprintf("%s://%s/%s/", scheme, domain, s.getSubdirectory())
+	}
+
+	return fmt.Sprintf("%s://%s/", scheme, domain)
 }
 
-// VerifySite verifies if a site is up and running without error
+// ErrMaintenanceMode Is returned by VerifySite when WordPress is up but in maintenance mode
+var ErrMaintenanceMode = errors.New("the site is in maintenance mode. Run 'kana maintenance off' to turn it off")
+
+// VerifySite verifies if a site is up and running without error, checking the "healthPath" option if it is set
 func (s *Site) VerifySite() (bool, error) {
+	return s.verifyURL(s.getHealthURL(), s.Settings.HealthStatus, s.Settings.VerifyRestAPI)
+}
 
-	caCert, err := os.ReadFile(s.rootCert)
+// getHealthURL Returns the URL that shows the site is ready, which is the site itself unless "healthPath" is set
+func (s *Site) getHealthURL() string {
+	return s.GetURL(false) + strings.TrimLeft(s.Settings.HealthPath, "/")
+}
+
+// newSiteHTTPClient Returns an HTTP client that trusts Kana's certificate for requests to the site. The site is always
+// local so, unlike other requests, these never go through a proxy from the app config or the environment.
+func (s *Site) newSiteHTTPClient() (*http.Client, error) {
+
+	client, err := appConfig.NewHTTPClient(s.DynamicConfig, s.rootCert)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	caCertPool := x509.NewCertPool()
-	caCertPool.AppendCertsFromPEM(caCert)
 
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	client.Transport.(*http.Transport).Proxy = nil
+
+	return client, nil
+}
+
+// getHealthClient Returns a copy of the client that doesn't follow redirects when the expected status is a redirect
+// itself, as following it would mean the status could never match
+func getHealthClient(client *http.Client, expectedStatus int) *http.Client {
+
+	if expectedStatus < 300 || expectedStatus > 399 {
+		return client
 	}
 
-	resp, err := client.Get(s.secureURL)
+	noRedirectClient := *client
+	noRedirectClient.CheckRedirect = func(request *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &noRedirectClient
+}
+
+// verifyURL Waits for the URL to respond with the expected status, also checking the site's REST API if verifyRestAPI is set
+func (s *Site) verifyURL(siteURL string, expectedStatus int, verifyRestAPI bool) (bool, error) {
+
+	client, err := s.newSiteHTTPClient()
 	if err != nil {
 		return false, err
 	}
 
 	tries := 0
 
-	for resp.StatusCode != 200 {
+	for {
 
-		resp, err = client.Get(s.secureURL)
+		resp, err := getHealthClient(client, expectedStatus).Get(siteURL)
 		if err != nil {
 			return false, err
 		}
 
-		if resp.StatusCode == 200 {
-			break
+		resp.Body.Close()
+
+		if resp.StatusCode == expectedStatus {
+
+			// A site that hasn't been installed yet redirects everything, including the REST API, to the installer
+			isInstaller := strings.HasSuffix(resp.Request.URL.Path, "/install.php")
+
+			if verifyRestAPI && !isInstaller {
+				err = s.verifyRestAPI(client)
+				if err != nil {
+					console.Warn("The site is up but its REST API isn't working: %s", err)
+				}
+			}
+
+			return true, nil
+		}
+
+		// WordPress sends a Retry-After header with its maintenance page, unlike a site that is still starting
+		if resp.StatusCode == http.StatusServiceUnavailable && len(resp.Header.Get("Retry-After")) > 0 {
+			return false, ErrMaintenanceMode
 		}
 
 		if tries == 30 {
@@ -170,85 +438,273 @@ func (s *Site) VerifySite() (bool, error) {
 
 		tries++
 		time.Sleep(1 * time.Second)
+	}
+}
+
+// WaitForSite Blocks until the site, or its "healthPath", responds without error and, if the "verifyRestAPI" option is set, its REST API
+// responds with JSON. Returns the last problem seen if the site isn't ready before the timeout.
+func (s *Site) WaitForSite(timeout time.Duration) error {
+
+	client, err := s.newSiteHTTPClient()
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+
+		err = s.checkSiteReady(client)
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the site wasn't ready after %s: %s", timeout, err)
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// checkSiteReady Returns an error if the site, or its REST API when it is verified, isn't responding correctly
+func (s *Site) checkSiteReady(client *http.Client) error {
+
+	healthURL := s.getHealthURL()
+
+	resp, err := getHealthClient(client, s.Settings.HealthStatus).Get(healthURL)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != s.Settings.HealthStatus {
+		return fmt.Errorf("%s returned %s instead of %d", healthURL, resp.Status, s.Settings.HealthStatus)
+	}
+
+	if strings.HasSuffix(resp.Request.URL.Path, "/install.php") {
+		return fmt.Errorf("WordPress hasn't been installed yet")
+	}
+
+	if s.Settings.VerifyRestAPI {
+		return s.verifyRestAPI(client)
+	}
+
+	return nil
+}
+
+// verifyRestAPI Returns an error if the site's REST API doesn't respond with JSON
+func (s *Site) verifyRestAPI(client *http.Client) error {
+
+	var err error
+
+	// Sites without pretty permalinks only serve the REST API through the rest_route parameter
+	for _, restURL := range []string{s.GetURL(false) + "wp-json/", s.GetURL(false) + "?rest_route=/"} {
 
+		err = checkJSONResponse(client, restURL)
+		if err == nil {
+			return nil
+		}
 	}
 
-	return true, nil
+	return err
+}
+
+// checkJSONResponse Returns an error if the URL doesn't respond with a 200 status and valid JSON
+func checkJSONResponse(client *http.Client, url string) error {
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if !json.Valid(body) {
+		return fmt.Errorf("%s didn't return valid JSON", url)
+	}
+
+	return nil
 }
 
 // OpenSite Opens the current site in a browser if it is running correctly
 func (s *Site) OpenSite() error {
 
+	// Use the URL the site is actually running with
+	if s.IsSiteRunning() && s.GetRunningConfig().Insecure {
+		s.setSetting("insecure", true)
+	}
+
 	_, err := s.VerifySite()
-	if err != nil {
+	if errors.Is(err, ErrMaintenanceMode) {
+		console.Warn("The site is in maintenance mode")
+	} else if err != nil {
 		return err
 	}
 
-	openURL(s.secureURL)
+	openURL(s.GetURL(false))
 
 	return nil
 }
 
-// InstallXdebug installs xdebug in the site's PHP container
-func (s *Site) InstallXdebug() (bool, error) {
+// RenameSite Renames the site, moving its files and updating its URLs in the database
+func (s *Site) RenameSite(newName string) error {
 
-	if !s.SiteConfig.GetBool("xdebug") {
-		return false, nil
+	newName = appConfig.SanitizeSiteName(newName)
+	if len(newName) == 0 {
+		return fmt.Errorf("invalid site name. Please enter a valid name for the site")
 	}
 
-	fmt.Println("Installing Xdebug...")
-
-	commands := []string{
-		"pecl list | grep xdebug",
-		"pecl install xdebug",
-		"docker-php-ext-enable xdebug",
-		"echo 'xdebug.start_with_request=yes' >> /usr/local/etc/php/php.ini",
-		"echo 'xdebug.mode=debug' >> /usr/local/etc/php/php.ini",
-		"echo 'xdebug.client_host=host.docker.internal' >> /usr/local/etc/php/php.ini",
-		"echo 'xdebug.discover_client_host=on' >> /usr/local/etc/php/php.ini",
-		"echo 'xdebug.start_with_request=trigger' >> /usr/local/etc/php/php.ini",
+	if newName == s.StaticConfig.SiteName {
+		return fmt.Errorf("the site is already named %s", newName)
 	}
 
-	for i, command := range commands {
+	newSiteDirectory := path.Join(s.StaticConfig.AppDirectory, "sites", newName)
+
+	if _, err := os.Stat(newSiteDirectory); !os.IsNotExist(err) {
+		return fmt.Errorf("a site named %s already exists. Please choose a different name", newName)
+	}
 
-		restart := false
+	oldDomain := s.siteDomain
+	isRunning := s.IsSiteRunning()
+	isNamedSite := s.StaticConfig.WorkingDirectory == s.StaticConfig.SiteDirectory
 
-		if i+1 == len(commands) {
-			restart = true
+	// The containers have to be recreated to pick up the new name
+	if isRunning {
+		err := s.StopWordPress()
+		if err != nil {
+			return err
 		}
+	}
+
+	err := os.Rename(s.StaticConfig.SiteDirectory, newSiteDirectory)
+	if err != nil {
+		return err
+	}
+
+	s.setSiteName(newName)
 
-		output, err := s.runCli(command, restart)
+	if isNamedSite {
+
+		// Named sites are linked to their own directory so the link has to follow it
+		err = s.writeSiteLink(newSiteDirectory)
 		if err != nil {
-			return false, err
+			return err
 		}
+	} else {
 
-		// Verify that the command ran correctly
-		if i == 0 && strings.Contains(output.StdOut, "xdebug") {
-			return false, nil
+		// Linked sites get their name from the folder so the new name is saved in the site's config
+		s.setSetting("name", newName)
+
+		err = s.writeSiteConfig()
+		if err != nil {
+			return err
 		}
 	}
 
-	return true, nil
+	// A site without a database has no URLs to update
+	if _, err = os.Stat(path.Join(newSiteDirectory, "database")); os.IsNotExist(err) {
+		return nil
+	}
+
+	console.Info("Updating site URLs...")
+
+	traefikClient, err := traefik.NewTraefik(s.StaticConfig, s.DynamicConfig)
+	if err != nil {
+		return err
+	}
+
+	err = traefikClient.StartTraefik()
+	if err != nil {
+		return err
+	}
+
+	err = s.StartWordPress()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.VerifySite()
+	if err != nil && !errors.Is(err, ErrMaintenanceMode) {
+		return err
+	}
+
+	searchReplaceCommand := []string{
+		"search-replace",
+		oldDomain,
+		s.siteDomain,
+		"--all-tables",
+	}
+
+	_, err = s.RunWPCli(searchReplaceCommand)
+	if err != nil {
+		return err
+	}
+
+	// Leave the site the way we found it
+	if !isRunning {
+		return s.StopWordPress()
+	}
+
+	return nil
 }
 
-// runCli Runs an arbitrary CLI command against the site's WordPress container
+// runCli Runs an arbitrary CLI command against the site's WordPress container. Commands run as root as they install
+// PHP extensions and change PHP's config, which the container's own user can't do when the "hostUser" option is on.
 func (s *Site) runCli(command string, restart bool) (docker.ExecResult, error) {
 
-	container := fmt.Sprintf("kana_%s_wordpress", s.StaticConfig.SiteName)
+	container := s.getContainerName("wordpress")
 
-	output, err := s.dockerClient.ContainerExec(container, []string{command})
+	output, err := s.dockerClient.ContainerExec(container, "root", []string{command})
 	if err != nil {
 		return docker.ExecResult{}, err
 	}
 
 	if restart {
-		_, err = s.dockerClient.ContainerRestart(container)
-		return output, err
+		return output, s.restartWordPress()
 	}
 
 	return output, nil
 }
 
+// reloadCommand Gracefully reloads Apache or PHP-FPM, whichever the WordPress image runs, so PHP settings and
+// extensions are applied without dropping connections. Exits with an error if neither is running.
+const reloadCommand = `if command -v apachectl >/dev/null 2>&1; then apachectl -k graceful; else case "$(cat /proc/1/comm)" in php-fpm*) kill -USR2 1;; *) exit 1;; esac; fi`
+
+// restartWordPress Applies PHP changes to the site's WordPress container. With the "restartMode" option set to "reload"
+// the web server is reloaded in place, falling back to restarting the container if that doesn't work.
+func (s *Site) restartWordPress() error {
+
+	container := s.getContainerName("wordpress")
+
+	if s.Settings.RestartMode == "reload" {
+
+		output, err := s.dockerClient.ContainerExec(container, "", []string{reloadCommand})
+		if err == nil && output.ExitCode == 0 {
+			return nil
+		}
+
+		if err == nil {
+			err = fmt.Errorf("exited with code %d: %s", output.ExitCode, strings.TrimSpace(output.StdErr))
+		}
+
+		console.Debug("Unable to reload %s, restarting it instead: %s", container, err)
+	}
+
+	_, err := s.dockerClient.ContainerRestart(container)
+
+	return err
+}
+
 // openURL opens the URL in the user's default browser based on which OS they're using
 func openURL(url string) error {
 
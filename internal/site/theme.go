@@ -0,0 +1,137 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+type ThemeInfo struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Update  string `json:"update"`
+	Version string `json:"version"`
+}
+
+// GetThemes Returns the themes installed on the site
+func (s *Site) GetThemes() ([]ThemeInfo, error) {
+
+	themes := []ThemeInfo{}
+
+	output, err := s.runThemeCommand("list", "--format=json")
+	if err != nil {
+		return themes, err
+	}
+
+	err = json.Unmarshal([]byte(output), &themes)
+
+	return themes, err
+}
+
+// InstallTheme Installs the given theme from WordPress.org, optionally activating it, and saves it to the site config
+func (s *Site) InstallTheme(theme string, activate bool) error {
+
+	command := []string{"install", theme}
+
+	if activate {
+		command = append(command, "--activate")
+	}
+
+	_, err := s.runThemeCommand(command...)
+	if err != nil {
+		return err
+	}
+
+	themes := s.Settings.Themes
+
+	if !appConfig.CheckString(theme, themes) {
+		s.setSetting("themes", append(themes, theme))
+	}
+
+	if activate {
+		s.setSetting("activeTheme", theme)
+	}
+
+	return s.writeSiteConfig()
+}
+
+// ActivateTheme Activates an installed theme and saves it as the site's active theme
+func (s *Site) ActivateTheme(theme string) error {
+
+	_, err := s.runThemeCommand("activate", theme)
+	if err != nil {
+		return err
+	}
+
+	s.setSetting("activeTheme", theme)
+
+	return s.writeSiteConfig()
+}
+
+// RemoveTheme Deletes the given theme from the site and removes it from the site config
+func (s *Site) RemoveTheme(theme string) error {
+
+	_, err := s.runThemeCommand("delete", theme)
+	if err != nil {
+		return err
+	}
+
+	themes := []string{}
+
+	for _, configTheme := range s.Settings.Themes {
+		if configTheme != theme {
+			themes = append(themes, configTheme)
+		}
+	}
+
+	s.setSetting("themes", themes)
+
+	if s.Settings.ActiveTheme == theme {
+		s.setSetting("activeTheme", "")
+	}
+
+	return s.writeSiteConfig()
+}
+
+// InstallDefaultThemes Installs the themes in the site's "themes" option and activates its "activeTheme"
+func (s *Site) InstallDefaultThemes() error {
+
+	for _, theme := range s.Settings.Themes {
+
+		_, err := s.runThemeCommand("install", theme)
+		if err != nil {
+			return err
+		}
+	}
+
+	activeTheme := s.Settings.ActiveTheme
+
+	if len(activeTheme) == 0 {
+		return nil
+	}
+
+	_, err := s.runThemeCommand("activate", activeTheme)
+
+	return err
+}
+
+// runThemeCommand Runs a "wp theme" command, returning an error if it fails
+func (s *Site) runThemeCommand(args ...string) (string, error) {
+
+	command := append([]string{"theme"}, args...)
+
+	statusCode, output, err := s.runWPCli(command, []mount.Mount{})
+	if err != nil {
+		return output, err
+	}
+
+	if statusCode != 0 {
+		return output, fmt.Errorf("unable to run wp %s: %s", strings.Join(command, " "), strings.TrimSpace(output))
+	}
+
+	return output, nil
+}
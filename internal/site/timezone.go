@@ -0,0 +1,68 @@
+package site
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	_ "time/tzdata" // Validate timezones even on systems without a zoneinfo database
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// validUTCOffset Matches the manual offsets WordPress offers alongside named timezones, such as UTC+5.5
+var validUTCOffset = regexp.MustCompile(`^UTC[+-]([0-9]|1[0-4])(\.5|\.75)?$`)
+
+// isValidTimezone Returns true if WordPress accepts the timezone, either a name such as Europe/Berlin or an offset such as UTC-5
+func isValidTimezone(timezone string) bool {
+
+	if validUTCOffset.MatchString(timezone) || timezone == "UTC" {
+		return true
+	}
+
+	// LoadLocation also accepts "Local" which isn't a timezone WordPress knows
+	if timezone == "Local" || !strings.Contains(timezone, "/") {
+		return false
+	}
+
+	_, err := time.LoadLocation(timezone)
+
+	return err == nil
+}
+
+// updateDateSettings Sets the timezone and date and time formats from the site config in WordPress
+func (s *Site) updateDateSettings() error {
+
+	options := [][]string{}
+
+	timezone := s.Settings.Timezone
+
+	// WordPress stores offsets separately from named timezones
+	if validUTCOffset.MatchString(timezone) {
+		options = append(options, []string{"timezone_string", ""}, []string{"gmt_offset", strings.TrimPrefix(timezone, "UTC")})
+	} else if len(timezone) > 0 {
+		options = append(options, []string{"timezone_string", timezone})
+	}
+
+	if dateFormat := s.Settings.DateFormat; len(dateFormat) > 0 {
+		options = append(options, []string{"date_format", dateFormat})
+	}
+
+	if timeFormat := s.Settings.TimeFormat; len(timeFormat) > 0 {
+		options = append(options, []string{"time_format", timeFormat})
+	}
+
+	for _, option := range options {
+
+		statusCode, output, err := s.runWPCli([]string{"option", "update", option[0], option[1]}, []mount.Mount{})
+		if err != nil {
+			return err
+		}
+
+		if statusCode != 0 {
+			return fmt.Errorf("unable to set %s: %s", option[0], strings.TrimSpace(output))
+		}
+	}
+
+	return nil
+}
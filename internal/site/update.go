@@ -0,0 +1,53 @@
+package site
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/console"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// UpdateWordPress Updates WordPress core, unless the "wordpressVersion" option pins it, and every plugin and theme to
+// their latest versions. Plugins and themes mounted from the site's folder are left alone.
+func (s *Site) UpdateWordPress() error {
+
+	updateCommands := [][]string{}
+
+	if version := s.Settings.WordPressVersion; version == "latest" || len(version) == 0 {
+		updateCommands = append(updateCommands, []string{"core", "update"}, []string{"core", "update-db"})
+	}
+
+	for _, extensionType := range []string{"plugin", "theme"} {
+
+		updateCommand := []string{extensionType, "update", "--all"}
+
+		if mountedExtensions := s.getMountedExtensions(extensionType); len(mountedExtensions) > 0 {
+			updateCommand = append(updateCommand, fmt.Sprintf("--exclude=%s", strings.Join(mountedExtensions, ",")))
+		}
+
+		updateCommands = append(updateCommands, updateCommand)
+	}
+
+	for _, updateCommand := range updateCommands {
+
+		spinner := console.StartSpinner("Running wp %s...", strings.Join(updateCommand, " "))
+
+		statusCode, output, err := s.runWPCli(updateCommand, []mount.Mount{})
+
+		spinner.Stop()
+
+		if err != nil {
+			return err
+		}
+
+		if statusCode != 0 {
+			return fmt.Errorf("unable to run wp %s: %s", strings.Join(updateCommand, " "), strings.TrimSpace(output))
+		}
+
+		console.Debug("%s", strings.TrimSpace(output))
+	}
+
+	return nil
+}
@@ -1,21 +1,41 @@
 package site
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path"
-
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+	"github.com/ChrisWiegman/kana-cli/internal/console"
 	"github.com/ChrisWiegman/kana-cli/internal/docker"
 	"github.com/ChrisWiegman/kana-cli/internal/traefik"
 
 	"github.com/docker/docker/api/types/mount"
 )
 
+var validSubdirectory = regexp.MustCompile(`^[a-zA-Z0-9_-]+(/[a-zA-Z0-9_-]+)*$`)
+
+var validTablePrefix = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+var tablePrefixDefinition = regexp.MustCompile(`(?m)^[ \t]*\$table_prefix\s*=.*$`)
+
+var validWordPressVersion = regexp.MustCompile(`^\d+\.\d+(\.\d+)?(-(alpha|beta|RC)\d*)?$`)
+
 type CurrentConfig struct {
-	Type   string
-	Local  bool
-	Xdebug bool
+	Type          string
+	Local         bool
+	Xdebug        bool
+	Insecure      bool
+	PHPExtensions []string
 }
 
 type PluginInfo struct {
@@ -28,34 +48,145 @@ type PluginInfo struct {
 // GetSiteContainers returns an array of strings containing the container names for the site
 func (s *Site) GetSiteContainers() []string {
 
-	return []string{
-		fmt.Sprintf("kana_%s_database", s.StaticConfig.SiteName),
-		fmt.Sprintf("kana_%s_wordpress", s.StaticConfig.SiteName),
+	containers := []string{
+		s.getContainerName("database"),
+		s.getContainerName("wordpress"),
+	}
+
+	for _, phpVersion := range s.getPHPVersions() {
+		containers = append(containers, s.getContainerName(fmt.Sprintf("wordpress_%s", s.getPHPVersionName(phpVersion))))
+	}
+
+	return containers
+}
+
+// getContainerDependencies Returns the names of the site's containers along with the containers each depends on
+func (s *Site) getContainerDependencies() []docker.ContainerConfig {
+
+	containers := []docker.ContainerConfig{}
+	databaseContainer := s.getContainerName("database")
+
+	for _, name := range s.GetSiteContainers() {
+
+		container := docker.ContainerConfig{Name: name}
+
+		if name != databaseContainer {
+			container.DependsOn = []string{databaseContainer}
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers
+}
+
+// FollowLogs Copies the logs of the named site container, such as "database" or "wordpress", to the writer, following new output if asked
+func (s *Site) FollowLogs(container string, follow bool, writer io.Writer) error {
+	return s.dockerClient.ContainerLogFollow(s.getContainerName(container), follow, writer)
+}
+
+// GetSiteContainerNames Returns the short names of the site's containers, such as "database" and "wordpress"
+func (s *Site) GetSiteContainerNames() []string {
+
+	names := []string{}
+
+	for _, container := range s.GetSiteContainers() {
+		names = append(names, strings.TrimPrefix(container, s.getContainerName("")))
+	}
+
+	return names
+}
+
+// getContainerName Returns the full name of the given site container, such as "wordpress"
+func (s *Site) getContainerName(container string) string {
+	return appConfig.GetContainerName(s.DynamicConfig, s.StaticConfig.SiteName, container)
+}
+
+// getNetworkName Returns the name of the network shared by all sites
+func (s *Site) getNetworkName() string {
+	return appConfig.GetPrefix(s.DynamicConfig)
+}
+
+// getPHPVersions Returns the valid PHP versions from the site's "phpVersions" option, each of which runs in its own WordPress container
+func (s *Site) getPHPVersions() []string {
+
+	phpVersions := []string{}
+
+	for _, phpVersion := range s.Settings.PHPVersions {
+
+		if !appConfig.CheckString(phpVersion, appConfig.ValidPHPVersions) {
+			console.Warn("Invalid PHP version %q in phpVersions. It will be skipped.", phpVersion)
+			continue
+		}
+
+		if !appConfig.CheckString(phpVersion, phpVersions) {
+			phpVersions = append(phpVersions, phpVersion)
+		}
+	}
+
+	return phpVersions
+}
+
+// getPHPVersionName Returns the name used in the container and host names for the given PHP version, such as php81
+func (s *Site) getPHPVersionName(phpVersion string) string {
+	return fmt.Sprintf("php%s", strings.ReplaceAll(phpVersion, ".", ""))
+}
+
+// getPHPVersionDomain Returns the domain of the container running the given PHP version, such as php81-mysite.sites.kana.li.
+// The version is joined to the site name with a dash rather than a dot as the certificate only covers a single level of
+// subdomains (*.sites.kana.li), so php81.mysite.sites.kana.li would fail TLS verification.
+func (s *Site) getPHPVersionDomain(phpVersion string) string {
+	return fmt.Sprintf("%s-%s.%s", s.getPHPVersionName(phpVersion), s.StaticConfig.SiteName, s.StaticConfig.AppDomain)
+}
+
+// GetPHPVersionURLs Returns the URL of each extra PHP version the site runs
+func (s *Site) GetPHPVersionURLs() map[string]string {
+
+	urls := map[string]string{}
+
+	for _, phpVersion := range s.getPHPVersions() {
+		urls[phpVersion] = s.getDomainURL(s.getPHPVersionDomain(phpVersion))
 	}
+
+	return urls
 }
 
 // IsSiteRunning Returns true if the site is up and running in Docker or false. Does not verify other errors
 func (s *Site) IsSiteRunning() bool {
 
-	containers, _ := s.dockerClient.ListContainers(s.StaticConfig.SiteName)
+	containers, _ := s.dockerClient.ListContainers(appConfig.GetSiteLabel(s.DynamicConfig), s.StaticConfig.SiteName)
 
 	return len(containers) != 0
 }
 
+// GetReceivedBytes Returns the number of bytes the site's WordPress container has received, which only grows as requests are made to the site
+func (s *Site) GetReceivedBytes() (uint64, error) {
+	return s.dockerClient.ContainerReceivedBytes(s.getContainerName("wordpress"))
+}
+
 // StopWordPress Stops the site in docker, destroying the containers when they close
 func (s *Site) StopWordPress() error {
 
-	wordPressContainers := s.GetSiteContainers()
+	// Stop WordPress before the database so it doesn't log connection errors while shutting down
+	wordPressContainers, err := docker.StopOrder(s.getContainerDependencies())
+	if err != nil {
+		return err
+	}
 
 	for _, wordPressContainer := range wordPressContainers {
-		_, err := s.dockerClient.ContainerStop(wordPressContainer)
+		_, err := s.dockerClient.ContainerStop(wordPressContainer.Name)
 		if err != nil {
 			return err
 		}
 	}
 
+	err = s.removeHostsEntries()
+	if err != nil {
+		console.Warn("Unable to remove the site from the hosts file: %s", err)
+	}
+
 	// If no other sites are running, also shut down the Traefik container
-	traefikClient, err := traefik.NewTraefik(s.StaticConfig)
+	traefikClient, err := traefik.NewTraefik(s.StaticConfig, s.DynamicConfig)
 	if err != nil {
 		return err
 	}
@@ -63,150 +194,107 @@ func (s *Site) StopWordPress() error {
 	return traefikClient.MaybeStopTraefik()
 }
 
-// getLocalAppDir Gets the absolute path to WordPress if the local flag or option has been set
-func getLocalAppDir() (string, error) {
+// RemoveContainers Stops and removes all of the site's containers, running or not, so the next start creates them
+// again from the current config. The site's database and files are kept as they live in the site's folders.
+func (s *Site) RemoveContainers() error {
 
-	cwd, err := os.Getwd()
+	wordPressContainers, err := docker.StopOrder(s.getContainerDependencies())
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	localAppDir := path.Join(cwd, "wordpress")
+	for _, wordPressContainer := range wordPressContainers {
+		_, err := s.dockerClient.ContainerStop(wordPressContainer.Name)
+		if err != nil {
+			return err
+		}
+	}
 
-	err = os.MkdirAll(localAppDir, 0750)
+	// Find the containers by label so ones no longer in the config, such as removed PHP versions, go too
+	containers, err := s.dockerClient.ListContainers(appConfig.GetSiteLabel(s.DynamicConfig), s.StaticConfig.SiteName)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return localAppDir, nil
-}
+	for _, container := range containers {
+		err = s.dockerClient.ContainerRemove(container)
+		if err != nil {
+			return err
+		}
+	}
 
-func (s *Site) getMounts(appDir, siteType string) ([]mount.Mount, error) {
+	return nil
+}
 
-	appVolumes := []mount.Mount{
-		{
-			Type:   mount.TypeBind,
-			Source: appDir,
-			Target: "/var/www/html",
-		},
-	}
+// RestartWordPress Restarts all of the site's containers in place, restarting each after the containers it depends on
+func (s *Site) RestartWordPress() error {
 
-	cwd, err := os.Getwd()
+	wordPressContainers, err := docker.SortContainers(s.getContainerDependencies())
 	if err != nil {
-		return appVolumes, err
+		return err
 	}
 
-	if siteType == "plugin" {
-		appVolumes = append(appVolumes, mount.Mount{
-			Type:   mount.TypeBind,
-			Source: cwd,
-			Target: path.Join("/var/www/html", "wp-content", "plugins", s.StaticConfig.SiteName),
-		})
+	for _, wordPressContainer := range wordPressContainers {
+
+		for _, dependency := range wordPressContainer.DependsOn {
+			err := s.waitForContainer(dependency)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := s.dockerClient.ContainerRestart(wordPressContainer.Name)
+		if err != nil {
+			return err
+		}
 	}
 
-	if siteType == "theme" {
-		appVolumes = append(appVolumes, mount.Mount{
-			Type:   mount.TypeBind,
-			Source: cwd,
-			Target: path.Join("/var/www/html", "wp-content", "themes", s.StaticConfig.SiteName),
-		})
+	return nil
+}
+
+// RestartContainer Restarts one of the site's containers, such as "wordpress" or "database", leaving the others running
+func (s *Site) RestartContainer(container string) error {
+
+	containers := s.GetSiteContainerNames()
+
+	if !appConfig.CheckString(container, containers) {
+		return fmt.Errorf("invalid container %q. Please choose one of %s", container, strings.Join(containers, ", "))
 	}
 
-	return appVolumes, nil
+	_, err := s.dockerClient.ContainerRestart(s.getContainerName(container))
+
+	return err
 }
 
-// StartWordPress Starts the WordPress containers
-func (s *Site) StartWordPress() error {
+// PauseSite Freezes the site's running containers so they stop using CPU without losing their state like StopWordPress does
+func (s *Site) PauseSite() error {
 
-	_, _, err := s.dockerClient.EnsureNetwork("kana")
+	// Pause WordPress before the database so no requests are cut off halfway through a query
+	wordPressContainers, err := docker.StopOrder(s.getContainerDependencies())
 	if err != nil {
 		return err
 	}
 
-	appDir := path.Join(s.StaticConfig.SiteDirectory, "app")
-	databaseDir := path.Join(s.StaticConfig.SiteDirectory, "database")
-
-	if s.IsLocalSite() {
-		appDir, err = getLocalAppDir()
+	for _, wordPressContainer := range wordPressContainers {
+		_, err := s.dockerClient.ContainerPause(wordPressContainer.Name)
 		if err != nil {
 			return err
 		}
-
-		// Replace wp-config.php with the container's file
-		_, err := os.Stat(path.Join(appDir, "wp-config.php"))
-		if err == nil {
-			os.Remove(path.Join(appDir, "wp-config.php"))
-		}
 	}
 
-	if err := os.MkdirAll(appDir, 0750); err != nil {
-		return err
-	}
+	return nil
+}
 
-	if err := os.MkdirAll(databaseDir, 0750); err != nil {
-		return err
-	}
+// ResumeSite Unfreezes the containers paused by PauseSite, starting with the database WordPress depends on
+func (s *Site) ResumeSite() error {
 
-	appVolumes, err := s.getMounts(appDir, s.SiteConfig.GetString("type"))
+	wordPressContainers, err := docker.SortContainers(s.getContainerDependencies())
 	if err != nil {
 		return err
 	}
 
-	wordPressContainers := []docker.ContainerConfig{
-		{
-			Name:        fmt.Sprintf("kana_%s_database", s.StaticConfig.SiteName),
-			Image:       "mariadb",
-			NetworkName: "kana",
-			HostName:    fmt.Sprintf("kana_%s_database", s.StaticConfig.SiteName),
-			Env: []string{
-				"MARIADB_ROOT_PASSWORD=password",
-				"MARIADB_DATABASE=wordpress",
-				"MARIADB_USER=wordpress",
-				"MARIADB_PASSWORD=wordpress",
-			},
-			Labels: map[string]string{
-				"kana.site": s.StaticConfig.SiteName,
-			},
-			Volumes: []mount.Mount{
-				{
-					Type:   mount.TypeBind,
-					Source: databaseDir,
-					Target: "/var/lib/mysql",
-				},
-			},
-		},
-		{
-			Name:        fmt.Sprintf("kana_%s_wordpress", s.StaticConfig.SiteName),
-			Image:       fmt.Sprintf("wordpress:php%s", s.SiteConfig.GetString("php")),
-			NetworkName: "kana",
-			HostName:    fmt.Sprintf("kana_%s_wordpress", s.StaticConfig.SiteName),
-			Env: []string{
-				fmt.Sprintf("WORDPRESS_DB_HOST=kana_%s_database", s.StaticConfig.SiteName),
-				"WORDPRESS_DB_USER=wordpress",
-				"WORDPRESS_DB_PASSWORD=wordpress",
-				"WORDPRESS_DB_NAME=wordpress",
-			},
-			Labels: map[string]string{
-				"traefik.enable": "true",
-				fmt.Sprintf("traefik.http.routers.wordpress-%s-http.entrypoints", s.StaticConfig.SiteName): "web",
-				fmt.Sprintf("traefik.http.routers.wordpress-%s-http.rule", s.StaticConfig.SiteName):        fmt.Sprintf("Host(`%s.%s`)", s.StaticConfig.SiteName, s.StaticConfig.AppDomain),
-				fmt.Sprintf("traefik.http.routers.wordpress-%s.entrypoints", s.StaticConfig.SiteName):      "websecure",
-				fmt.Sprintf("traefik.http.routers.wordpress-%s.rule", s.StaticConfig.SiteName):             fmt.Sprintf("Host(`%s.%s`)", s.StaticConfig.SiteName, s.StaticConfig.AppDomain),
-				fmt.Sprintf("traefik.http.routers.wordpress-%s.tls", s.StaticConfig.SiteName):              "true",
-				"kana.site": s.StaticConfig.SiteName,
-			},
-			Volumes: appVolumes,
-		},
-	}
-
-	for _, container := range wordPressContainers {
-
-		err := s.dockerClient.EnsureImage(container.Image)
-		if err != nil {
-			return err
-		}
-
-		_, err = s.dockerClient.ContainerRun(container)
+	for _, wordPressContainer := range wordPressContainers {
+		_, err := s.dockerClient.ContainerUnpause(wordPressContainer.Name)
 		if err != nil {
 			return err
 		}
@@ -215,136 +303,1332 @@ func (s *Site) StartWordPress() error {
 	return nil
 }
 
-// InstallWordPress Installs and configures WordPress core
-func (s *Site) InstallWordPress() error {
+// IsSitePaused Returns true if the site's WordPress container has been paused with PauseSite
+func (s *Site) IsSitePaused() bool {
+	return s.dockerClient.IsContainerPaused(s.getContainerName("wordpress"))
+}
 
-	fmt.Println("Finishing WordPress setup...")
+// getExtraHosts Returns the extra host entries needed for the containers to reach the host machine.
+// Docker Desktop provides host.docker.internal itself but stock Docker on Linux needs it mapped to the host gateway.
+func getExtraHosts() []string {
 
-	setupCommand := []string{
-		"core",
-		"install",
-		fmt.Sprintf("--url=%s", s.GetURL(false)),
-		fmt.Sprintf("--title=Kana Development %s: %s", s.SiteConfig.GetString("type"), s.StaticConfig.SiteName),
-		fmt.Sprintf("--admin_user=%s", s.DynamicConfig.GetString("admin.username")),
-		fmt.Sprintf("--admin_password=%s", s.DynamicConfig.GetString("admin.password")),
-		fmt.Sprintf("--admin_email=%s", s.DynamicConfig.GetString("admin.email")),
+	if runtime.GOOS == "linux" {
+		return []string{"host.docker.internal:host-gateway"}
 	}
 
-	_, err := s.RunWPCli(setupCommand)
-	return err
+	return []string{}
 }
 
-// InstallDefaultPlugins Installs a list of WordPress plugins
-func (s *Site) InstallDefaultPlugins() error {
+// getSubdirectory Returns the directory WordPress is installed in relative to the root of the site, or an empty string for the root
+func (s *Site) getSubdirectory() string {
+	return s.Settings.Subdirectory
+}
 
-	for _, plugin := range s.SiteConfig.GetStringSlice("plugins") {
+// getWordPressPath Returns the path of the WordPress install inside the containers
+func (s *Site) getWordPressPath() string {
+	return path.Join("/var/www/html", s.getSubdirectory())
+}
 
-		setupCommand := []string{
-			"plugin",
-			"install",
-			"--activate",
-			plugin,
-		}
+// getURLConstants Returns the WORDPRESS_CONFIG_EXTRA variable that points WP_HOME and WP_SITEURL to the given URL
+func getURLConstants(url string) string {
 
-		_, err := s.RunWPCli(setupCommand)
-		if err != nil {
-			return err
-		}
+	url = strings.TrimSuffix(url, "/")
+
+	return fmt.Sprintf("WORDPRESS_CONFIG_EXTRA=define('WP_HOME', '%s'); define('WP_SITEURL', '%s');", url, url)
+}
+
+// getContainerUser Returns the host's user and group IDs for containers that write to the site's files when the hostUser option is set.
+// Docker Desktop already maps file ownership to the host user so this only applies on Linux.
+func (s *Site) getContainerUser() string {
+
+	if runtime.GOOS != "linux" || !s.Settings.HostUser {
+		return ""
 	}
 
-	return nil
+	return fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
 }
 
-// RunWPCli Runs a wp-cli command returning it's output and any errors
-func (s *Site) RunWPCli(command []string) (string, error) {
+// getLocalAppDir Gets the absolute path to WordPress if the local flag or option has been set
+func (s *Site) getLocalAppDir() (string, error) {
+
+	localAppDir := path.Join(s.StaticConfig.WorkingDirectory, "wordpress")
 
-	_, _, err := s.dockerClient.EnsureNetwork("kana")
+	err := os.MkdirAll(localAppDir, 0750)
 	if err != nil {
 		return "", err
 	}
 
-	siteDir := path.Join(s.StaticConfig.AppDirectory, "sites", s.StaticConfig.SiteName)
-	appDir := path.Join(siteDir, "app")
-	runningConfig := s.GetRunningConfig()
+	return localAppDir, nil
+}
 
-	if runningConfig.Local {
-		appDir, err = getLocalAppDir()
+func (s *Site) getMounts(appDir, siteType string) ([]mount.Mount, error) {
+
+	appVolumes := []mount.Mount{
+		{
+			Type:   mount.TypeBind,
+			Source: appDir,
+			Target: "/var/www/html",
+		},
+	}
+
+	if siteType == "plugin" {
+		appVolumes = append(appVolumes, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: s.StaticConfig.WorkingDirectory,
+			Target: path.Join(s.getWordPressPath(), "wp-content", "plugins", s.StaticConfig.SiteName),
+		})
+	}
+
+	if siteType == "theme" {
+		appVolumes = append(appVolumes, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: s.StaticConfig.WorkingDirectory,
+			Target: path.Join(s.getWordPressPath(), "wp-content", "themes", s.StaticConfig.SiteName),
+		})
+	}
+
+	if siteType == "plugins" || siteType == "themes" {
+		extensionMounts, err := s.getExtensionMounts(siteType)
 		if err != nil {
-			return "", err
+			return appVolumes, err
 		}
+
+		appVolumes = append(appVolumes, extensionMounts...)
 	}
 
-	appVolumes, err := s.getMounts(appDir, runningConfig.Type)
+	muPluginsMount, hasMUPlugins, err := s.getMUPluginsMount()
 	if err != nil {
-		return "", err
+		return appVolumes, err
 	}
 
-	fullCommand := []string{
-		"wp",
-		"--path=/var/www/html",
+	if hasMUPlugins {
+		appVolumes = append(appVolumes, muPluginsMount)
 	}
 
-	fullCommand = append(fullCommand, command...)
+	xdebugMount, hasXdebug, err := s.getXdebugMount()
+	if err != nil {
+		return appVolumes, err
+	}
 
-	container := docker.ContainerConfig{
-		Name:        fmt.Sprintf("kana_%s_wordpress_cli", s.StaticConfig.SiteName),
-		Image:       fmt.Sprintf("wordpress:cli-php%s", s.DynamicConfig.GetString("php")),
-		NetworkName: "kana",
-		HostName:    fmt.Sprintf("kana_%s_wordpress_cli", s.StaticConfig.SiteName),
-		Command:     fullCommand,
-		Env: []string{
-			fmt.Sprintf("WORDPRESS_DB_HOST=kana_%s_database", s.StaticConfig.SiteName),
-			"WORDPRESS_DB_USER=wordpress",
-			"WORDPRESS_DB_PASSWORD=wordpress",
-			"WORDPRESS_DB_NAME=wordpress",
-		},
-		Labels: map[string]string{
-			"kana.site": s.StaticConfig.SiteName,
-		},
-		Volumes: appVolumes,
+	if hasXdebug {
+		appVolumes = append(appVolumes, xdebugMount)
 	}
 
-	err = s.dockerClient.EnsureImage(container.Image)
+	uploadsMount, hasSharedUploads, err := s.getSharedUploadsMount()
 	if err != nil {
-		return "", err
+		return appVolumes, err
 	}
 
-	_, output, err := s.dockerClient.ContainerRunAndClean(container)
-	if err != nil {
-		return "", err
+	if hasSharedUploads {
+		appVolumes = append(appVolumes, uploadsMount)
 	}
 
-	return output, nil
+	return appVolumes, nil
 }
 
-// GetInstalledWordPressPlugins Returns a list of the plugins that have been installed on the site
-func (s *Site) GetInstalledWordPressPlugins() ([]string, error) {
+// getSharedUploadsDirectory Returns the absolute path of the folder in the site's "uploads.directory" option,
+// relative to the site's folder, or an empty string if the site keeps its own uploads
+func (s *Site) getSharedUploadsDirectory() string {
 
-	commands := []string{
-		"plugin",
-		"list",
-		"--format=json",
+	uploadsDirectory := s.Settings.Uploads.Directory
+	if len(uploadsDirectory) == 0 {
+		return ""
 	}
 
-	commandOutput, err := s.RunWPCli(commands)
-	if err != nil {
-		return []string{}, err
+	if !filepath.IsAbs(uploadsDirectory) {
+		uploadsDirectory = filepath.Join(s.StaticConfig.WorkingDirectory, uploadsDirectory)
 	}
 
-	rawPlugins := []PluginInfo{}
-	plugins := []string{}
+	return uploadsDirectory
+}
 
-	err = json.Unmarshal([]byte(commandOutput), &rawPlugins)
-	if err != nil {
-		return []string{}, err
+// getSharedUploadsMount Returns the mount for the shared uploads folder in the site's "uploads.directory" option over
+// wp-content/uploads, read-only if "uploads.readOnly" is set. Returns false if the option isn't set.
+func (s *Site) getSharedUploadsMount() (mount.Mount, bool, error) {
+
+	uploadsDirectory := s.getSharedUploadsDirectory()
+	if len(uploadsDirectory) == 0 {
+		return mount.Mount{}, false, nil
 	}
 
-	for _, plugin := range rawPlugins {
+	fileInfo, err := os.Stat(uploadsDirectory)
+	if err != nil || !fileInfo.IsDir() {
+		return mount.Mount{}, false, fmt.Errorf("the shared uploads folder %s doesn't exist. Please create it or change the uploads.directory option", uploadsDirectory)
+	}
 
-		if plugin.Status != "dropin" && plugin.Name != s.StaticConfig.SiteName && plugin.Name != "hello" && plugin.Name != "akismet" {
-			plugins = append(plugins, plugin.Name)
-		}
+	return mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   uploadsDirectory,
+		Target:   path.Join(s.getWordPressPath(), "wp-content", "uploads"),
+		ReadOnly: s.Settings.Uploads.ReadOnly,
+	}, true, nil
+}
+
+// getMUPluginsMount Returns the mount for the folder in the site's "muPlugins" option, relative to the site's folder,
+// over wp-content/mu-plugins. Returns false if the option isn't set.
+func (s *Site) getMUPluginsMount() (mount.Mount, bool, error) {
+
+	muPluginsDirectory := s.Settings.MUPlugins
+	if len(muPluginsDirectory) == 0 {
+		return mount.Mount{}, false, nil
 	}
 
-	return plugins, nil
+	if !filepath.IsAbs(muPluginsDirectory) {
+		muPluginsDirectory = filepath.Join(s.StaticConfig.WorkingDirectory, muPluginsDirectory)
+	}
+
+	fileInfo, err := os.Stat(muPluginsDirectory)
+	if err != nil || !fileInfo.IsDir() {
+		return mount.Mount{}, false, fmt.Errorf("the muPlugins folder %s doesn't exist. Please create it or change the muPlugins option", muPluginsDirectory)
+	}
+
+	return mount.Mount{
+		Type:   mount.TypeBind,
+		Source: muPluginsDirectory,
+		Target: path.Join(s.getWordPressPath(), "wp-content", "mu-plugins"),
+	}, true, nil
+}
+
+// updateGitignore Adds the local WordPress files to the .gitignore file in the working directory, creating it if needed
+func (s *Site) updateGitignore() error {
+
+	gitignoreFile := path.Join(s.StaticConfig.WorkingDirectory, ".gitignore")
+	entries := []string{"/wordpress"}
+
+	contents, err := os.ReadFile(gitignoreFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Compare entries without surrounding slashes so "wordpress", "/wordpress" and "/wordpress/" all count as the same
+	existingEntries := []string{}
+
+	for _, line := range strings.Split(string(contents), "\n") {
+		existingEntries = append(existingEntries, strings.Trim(strings.TrimSpace(line), "/"))
+	}
+
+	newEntries := []string{}
+
+	for _, entry := range entries {
+		if !appConfig.CheckString(strings.Trim(entry, "/"), existingEntries) {
+			newEntries = append(newEntries, entry)
+		}
+	}
+
+	if len(newEntries) == 0 {
+		return nil
+	}
+
+	newContents := ""
+
+	if len(contents) > 0 && !strings.HasSuffix(string(contents), "\n") {
+		newContents = "\n"
+	}
+
+	newContents += fmt.Sprintf("# Kana\n%s\n", strings.Join(newEntries, "\n"))
+
+	file, err := os.OpenFile(gitignoreFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(newContents)
+
+	return err
+}
+
+// prepareWPConfig Makes sure an existing wp-config.php file in a local site will work with the site's containers
+func (s *Site) prepareWPConfig(appDir string) error {
+
+	wpConfigFile := path.Join(appDir, "wp-config.php")
+
+	wpConfig, err := os.ReadFile(wpConfigFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	// Keep the user's file, only pointing it to the site's database
+	if s.Settings.KeepConfig {
+
+		dbConstants := map[string]string{
+			"DB_NAME":     databaseName,
+			"DB_USER":     databaseUser,
+			"DB_PASSWORD": databasePassword,
+			"DB_HOST":     s.getContainerName("database"),
+		}
+
+		for constant, value := range dbConstants {
+			constantDefinition := regexp.MustCompile(fmt.Sprintf(`(?m)^[ \t]*define\(\s*['"]%s['"].*$`, constant))
+			wpConfig = constantDefinition.ReplaceAll(wpConfig, []byte(fmt.Sprintf("define( '%s', '%s' );", constant, value)))
+		}
+
+		wpConfig = tablePrefixDefinition.ReplaceAll(wpConfig, []byte(fmt.Sprintf("$table_prefix = '%s';", s.Settings.TablePrefix)))
+
+		return os.WriteFile(wpConfigFile, wpConfig, 0644)
+	}
+
+	// Files generated by the WordPress container can be replaced but back up anything else before removing it
+	if !bytes.Contains(wpConfig, []byte("getenv_docker(")) {
+
+		backupFile := path.Join(appDir, fmt.Sprintf("wp-config.php.%s.bak", time.Now().Format("20060102150405")))
+
+		console.Warn("Replacing wp-config.php with the container's version. Your file has been backed up to %s", backupFile)
+
+		err = os.WriteFile(backupFile, wpConfig, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(wpConfigFile)
+}
+
+// getDatabaseEnv Returns the environment variables the wp-config.php generated by the WordPress image reads
+// to connect to the site's database
+func (s *Site) getDatabaseEnv() []string {
+
+	return []string{
+		fmt.Sprintf("WORDPRESS_DB_HOST=%s", s.getContainerName("database")),
+		fmt.Sprintf("WORDPRESS_DB_USER=%s", databaseUser),
+		fmt.Sprintf("WORDPRESS_DB_PASSWORD=%s", databasePassword),
+		fmt.Sprintf("WORDPRESS_DB_NAME=%s", databaseName),
+		fmt.Sprintf("WORDPRESS_TABLE_PREFIX=%s", s.Settings.TablePrefix),
+	}
+}
+
+// getWordPressLabels Returns the labels for a WordPress container including the Traefik routers for the given domain
+func (s *Site) getWordPressLabels(routerName, domain string) map[string]string {
+
+	siteName := s.StaticConfig.SiteName
+	hostRule := fmt.Sprintf("Host(`%s`)", domain)
+
+	if len(s.getSubdirectory()) > 0 {
+		hostRule = fmt.Sprintf("%s && PathPrefix(`/%s`)", hostRule, s.getSubdirectory())
+	}
+	httpRouter := fmt.Sprintf("traefik.http.routers.%s-http", routerName)
+	secureRouter := fmt.Sprintf("traefik.http.routers.%s", routerName)
+
+	labels := map[string]string{
+		"traefik.enable":                        "true",
+		"traefik.docker.network":                s.getNetworkName(),
+		httpRouter + ".entrypoints":             "web",
+		httpRouter + ".rule":                    hostRule,
+		appConfig.GetSiteLabel(s.DynamicConfig): siteName,
+	}
+
+	authMiddlewares := []string{}
+
+	// Every router of the site, including those of its extra PHP versions, uses the same middleware so the credentials
+	// protect each domain the site can be reached at
+	basicAuth := s.Settings.Traefik.BasicAuth
+	if len(basicAuth) > 0 {
+		authMiddleware := fmt.Sprintf("wordpress-%s-auth", siteName)
+		labels[fmt.Sprintf("traefik.http.middlewares.%s.basicauth.users", authMiddleware)] = strings.Join(basicAuth, ",")
+		authMiddlewares = append(authMiddlewares, authMiddleware+"@docker")
+	}
+
+	middlewares := append(append([]string{}, authMiddlewares...), s.Settings.Traefik.Middlewares...)
+
+	routers := []string{httpRouter}
+
+	// Sites served over plain http don't need the TLS router or the redirect to it
+	if s.Settings.Insecure {
+		if len(middlewares) > 0 {
+			labels[httpRouter+".middlewares"] = strings.Join(middlewares, ",")
+		}
+	} else {
+		labels[httpRouter+".middlewares"] = strings.Join(append([]string{"redirect-to-https@file"}, authMiddlewares...), ",")
+		labels[secureRouter+".entrypoints"] = "websecure"
+		labels[secureRouter+".rule"] = hostRule
+		labels[secureRouter+".tls"] = "true"
+
+		if len(middlewares) > 0 {
+			labels[secureRouter+".middlewares"] = strings.Join(middlewares, ",")
+		}
+
+		routers = append(routers, secureRouter)
+	}
+
+	if priority := s.Settings.Traefik.Priority; priority > 0 {
+		for _, router := range routers {
+			labels[router+".priority"] = strconv.Itoa(priority)
+		}
+	}
+
+	return labels
+}
+
+// addCustomLabels Adds the labels from the site's "labels" option, which never include Kana's own, to the given labels
+func (s *Site) addCustomLabels(labels map[string]string) map[string]string {
+
+	for label, value := range s.Settings.Labels {
+		labels[label] = value
+	}
+
+	return labels
+}
+
+// getDatabaseMount Returns the mount for the database files, which is the given directory unless "database.ephemeral"
+// is set, in which case the files are kept in memory
+func (s *Site) getDatabaseMount(databaseDir string) mount.Mount {
+
+	if s.Settings.Database.Ephemeral {
+		return mount.Mount{
+			Type:   mount.TypeTmpfs,
+			Target: "/var/lib/mysql",
+		}
+	}
+
+	return mount.Mount{
+		Type:   mount.TypeBind,
+		Source: databaseDir,
+		Target: "/var/lib/mysql",
+	}
+}
+
+// StartWordPress Starts the WordPress containers
+func (s *Site) StartWordPress() error {
+
+	_, _, err := s.dockerClient.EnsureNetworkWithOptions(s.getNetworkName(), traefik.GetNetworkOptions(s.DynamicConfig))
+	if err != nil {
+		return err
+	}
+
+	appDir := path.Join(s.StaticConfig.SiteDirectory, "app")
+	databaseDir := path.Join(s.StaticConfig.SiteDirectory, "database")
+
+	if s.IsLocalSite() {
+		appDir, err = s.getLocalAppDir()
+		if err != nil {
+			return err
+		}
+
+		err = s.prepareWPConfig(path.Join(appDir, s.getSubdirectory()))
+		if err != nil {
+			return err
+		}
+
+		if s.Settings.Gitignore {
+			err = s.updateGitignore()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.MkdirAll(appDir, 0750); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(databaseDir, 0750); err != nil {
+		return err
+	}
+
+	appVolumes, err := s.getMounts(appDir, s.Settings.Type)
+	if err != nil {
+		return err
+	}
+
+	// WordPress doesn't lock its uploads so sites writing to the same folder can overwrite each other's files
+	if len(s.getSharedUploadsDirectory()) > 0 && !s.Settings.Uploads.ReadOnly {
+		console.Warn("The site shares its uploads with %s. Files uploaded with the same name by another site using the folder will replace each other. Set uploads.readOnly to true if the site doesn't need to upload media.", s.getSharedUploadsDirectory())
+	}
+
+	databasePorts, err := s.getDatabasePorts()
+	if err != nil {
+		return err
+	}
+
+	// Check the script before pulling anything so a mistake doesn't have to wait for the images
+	entrypointScript, err := s.getEntrypointScript()
+	if err != nil {
+		return err
+	}
+
+	if s.Settings.Database.Ephemeral {
+		console.Warn("The database.ephemeral option keeps the site's database in memory. Everything in it is lost when the site stops or the database container restarts.")
+	}
+
+	wordPressImage, err := s.getWordPressImage(s.Settings.PHP)
+	if err != nil {
+		return err
+	}
+
+	wordPressContainers := []docker.ContainerConfig{
+		{
+			Name:           s.getContainerName("database"),
+			Image:          appConfig.GetImage(s.DynamicConfig, "database", ""),
+			Ports:          databasePorts,
+			NetworkName:    s.getNetworkName(),
+			NetworkAliases: s.Settings.Aliases.Database,
+			HostName:       s.getContainerName("database"),
+			Env: []string{
+				fmt.Sprintf("MARIADB_ROOT_PASSWORD=%s", databaseRootPassword),
+				fmt.Sprintf("MARIADB_DATABASE=%s", databaseName),
+				fmt.Sprintf("MARIADB_USER=%s", databaseUser),
+				fmt.Sprintf("MARIADB_PASSWORD=%s", databasePassword),
+			},
+			Labels: s.addCustomLabels(map[string]string{
+				appConfig.GetSiteLabel(s.DynamicConfig): s.StaticConfig.SiteName,
+			}),
+			Volumes: []mount.Mount{
+				s.getDatabaseMount(databaseDir),
+			},
+		},
+		{
+			Name:           s.getContainerName("wordpress"),
+			Image:          wordPressImage,
+			NetworkName:    s.getNetworkName(),
+			NetworkAliases: s.Settings.Aliases.WordPress,
+			HostName:       s.getContainerName("wordpress"),
+			Env:            append(s.getDatabaseEnv(), appConfig.GetProxyEnv(s.DynamicConfig)...),
+			Labels:         s.addCustomLabels(s.getWordPressLabels(fmt.Sprintf("wordpress-%s", s.StaticConfig.SiteName), s.siteDomain)),
+			Volumes:        appVolumes,
+			ExtraHosts:     getExtraHosts(),
+			WorkingDir:     s.getWordPressPath(),
+			User:           s.getContainerUser(),
+			Command:        s.getWordPressCommand(),
+			DependsOn:      []string{s.getContainerName("database")},
+		},
+	}
+
+	wordPressContainer := findContainer(wordPressContainers, s.getContainerName("wordpress"))
+
+	// The wp-cli image has to match the PHP version the site is running, even after the config changes
+	wordPressContainer.Labels[appConfig.GetPHPLabel(s.DynamicConfig)] = s.Settings.PHP
+
+	// WordPress in a subdirectory needs its URL set or it would link to the root of the site
+	if len(s.getSubdirectory()) > 0 {
+		wordPressContainer.Env = append(wordPressContainer.Env, getURLConstants(s.GetURL(false)))
+	}
+
+	// Copied as adding containers to the list below can move it
+	mainContainer := *wordPressContainer
+
+	// Each extra PHP version gets its own WordPress container sharing the same files and database
+	for _, phpVersion := range s.getPHPVersions() {
+
+		versionContainer := mainContainer
+		versionName := s.getPHPVersionName(phpVersion)
+		versionDomain := s.getPHPVersionDomain(phpVersion)
+		versionURL := s.getDomainURL(versionDomain)
+
+		versionContainer.Name = s.getContainerName(fmt.Sprintf("wordpress_%s", versionName))
+		versionContainer.HostName = versionContainer.Name
+		versionContainer.Image, err = s.getWordPressImage(phpVersion)
+		if err != nil {
+			return err
+		}
+		versionContainer.NetworkAliases = []string{}
+		versionContainer.Labels = s.addCustomLabels(s.getWordPressLabels(fmt.Sprintf("wordpress-%s-%s", s.StaticConfig.SiteName, versionName), versionDomain))
+
+		// WordPress redirects to the URL saved in the database unless it is overridden for this container
+		versionContainer.Env = []string{}
+
+		for _, env := range mainContainer.Env {
+			if !strings.HasPrefix(env, "WORDPRESS_CONFIG_EXTRA=") {
+				versionContainer.Env = append(versionContainer.Env, env)
+			}
+		}
+
+		versionContainer.Env = append(versionContainer.Env, getURLConstants(versionURL))
+
+		wordPressContainers = append(wordPressContainers, versionContainer)
+	}
+
+	wordPressContainers, err = docker.SortContainers(wordPressContainers)
+	if err != nil {
+		return err
+	}
+
+	images := []string{}
+
+	for _, container := range wordPressContainers {
+		images = append(images, container.Image)
+	}
+
+	err = s.dockerClient.EnsureImages(images)
+	if err != nil {
+		return err
+	}
+
+	// The image's entrypoint can only be read once the image has been pulled
+	if len(entrypointScript) > 0 {
+		for i := range wordPressContainers {
+			if wordPressContainers[i].Name != s.getContainerName("database") {
+				err = s.setEntrypoint(&wordPressContainers[i], entrypointScript)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if s.Settings.Hosts {
+		err = s.addHostsEntries()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Pull the wp-cli image while the containers start so the first command doesn't have to wait for it
+	s.dockerClient.EnsureImageInBackground(s.getCLIImage())
+
+	spinner := console.StartSpinner("Starting the site's containers...")
+	defer spinner.Stop()
+
+	for _, container := range wordPressContainers {
+
+		for _, dependency := range container.DependsOn {
+			err := s.waitForContainer(dependency)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := s.dockerClient.ContainerRun(container)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findContainer Returns the container with the given name from the list so it can be changed in place, or nil if it isn't there
+func findContainer(containers []docker.ContainerConfig, name string) *docker.ContainerConfig {
+
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+
+	return nil
+}
+
+// getWordPressCommand Returns the site's "command" option for the WordPress container. An empty command uses the image's default.
+func (s *Site) getWordPressCommand() []string {
+
+	command := s.Settings.Command
+
+	if len(command) > 0 {
+		console.Warn("Replacing the WordPress container's default command with %q. The site may not start if it doesn't run a web server on port 80.", strings.Join(command, " "))
+	}
+
+	return command
+}
+
+// waitForContainer Waits for the named container to be ready to accept connections from the containers that depend on it
+func (s *Site) waitForContainer(containerName string) error {
+
+	if containerName == s.getContainerName("database") {
+		return s.waitForDatabase()
+	}
+
+	for tries := 0; tries < 30; tries++ {
+
+		_, isRunning := s.dockerClient.IsContainerRunning(containerName)
+		if isRunning {
+			return nil
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+
+	return fmt.Errorf("timeout reached. %s didn't start", containerName)
+}
+
+type InstallResult struct {
+	URL       string `json:"url"`
+	AdminURL  string `json:"adminURL"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	Email     string `json:"email"`
+	Version   string `json:"version"`
+	Installed bool   `json:"installed"`
+}
+
+// getAdminURL Returns the URL of the site's dashboard
+func (s *Site) getAdminURL() string {
+	return s.GetURL(false) + "wp-admin/"
+}
+
+// InstallWordPress Installs WordPress if the site's database is empty and applies the site's WordPress settings.
+// Returns the site's URLs, admin credentials and WordPress version along with whether a fresh install was run.
+func (s *Site) InstallWordPress() (InstallResult, error) {
+
+	result := InstallResult{
+		URL:      s.GetURL(false),
+		AdminURL: s.getAdminURL(),
+		Username: s.DynamicConfig.GetString("admin.username"),
+		Password: s.DynamicConfig.GetString("admin.password"),
+		Email:    s.DynamicConfig.GetString("admin.email"),
+	}
+
+	spinner := console.StartSpinner("Finishing WordPress setup...")
+
+	err := s.waitForDatabase()
+
+	spinner.Stop()
+
+	if err != nil {
+		return result, err
+	}
+
+	isInstalled, err := s.IsWordPressInstalled()
+	if err != nil {
+		return result, err
+	}
+
+	// Only install on a fresh database so restarting a site can't fail on a duplicate install
+	if !isInstalled {
+
+		err = s.checkTablePrefix()
+		if err != nil {
+			return result, err
+		}
+
+		setupCommand := []string{
+			"core",
+			"install",
+			fmt.Sprintf("--url=%s", result.URL),
+			fmt.Sprintf("--title=Kana Development %s: %s", s.Settings.Type, s.StaticConfig.SiteName),
+			fmt.Sprintf("--admin_user=%s", result.Username),
+			fmt.Sprintf("--admin_password=%s", result.Password),
+			fmt.Sprintf("--admin_email=%s", result.Email),
+		}
+
+		statusCode, output, err := s.runWPCli(setupCommand, []mount.Mount{})
+		if err != nil {
+			return result, err
+		}
+
+		if statusCode != 0 {
+			return result, fmt.Errorf("unable to install WordPress: %s", output)
+		}
+
+		result.Installed = true
+	}
+
+	err = s.updateWordPressVersion()
+	if err != nil {
+		return result, err
+	}
+
+	err = s.updateDateSettings()
+	if err != nil {
+		return result, err
+	}
+
+	// Seed the database on the first install unless the site wants it seeded every time
+	if !isInstalled || s.Settings.Database.SeedAlways {
+		err = s.seedDatabase()
+		if err != nil {
+			return result, err
+		}
+	}
+
+	statusCode, version, err := s.runWPCli([]string{"core", "version"}, []mount.Mount{})
+	if err != nil {
+		return result, err
+	}
+
+	if statusCode != 0 {
+		return result, fmt.Errorf("unable to get the WordPress version: %s", strings.TrimSpace(version))
+	}
+
+	result.Version = strings.TrimSpace(version)
+
+	return result, nil
+}
+
+// updateWordPressVersion Switches WordPress to the version set in the "wordpressVersion" option if it isn't "latest"
+func (s *Site) updateWordPressVersion() error {
+
+	version := s.Settings.WordPressVersion
+
+	if version == "trunk" {
+		version = "nightly"
+	}
+
+	if version == "latest" || len(version) == 0 {
+		return nil
+	}
+
+	if version != "nightly" && !validWordPressVersion.MatchString(version) {
+		return fmt.Errorf("invalid WordPress version %q. Please use \"latest\", \"nightly\" or a version number such as 6.0.2", version)
+	}
+
+	// Nightly builds are always updated to pick up the latest changes
+	if version != "nightly" {
+
+		currentVersion, err := s.RunWPCli([]string{"core", "version"})
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(currentVersion) == version {
+			return nil
+		}
+	}
+
+	spinner := console.StartSpinner("Installing WordPress %s...", version)
+
+	updateCommand := []string{
+		"core",
+		"update",
+		fmt.Sprintf("--version=%s", version),
+		"--force",
+	}
+
+	statusCode, output, err := s.runWPCli(updateCommand, []mount.Mount{})
+
+	spinner.Stop()
+
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 0 {
+		return fmt.Errorf("unable to install WordPress %s: %s", version, output)
+	}
+
+	if version == "nightly" {
+		console.Warn("This site is running a nightly development build of WordPress.")
+	}
+
+	return nil
+}
+
+// waitForDatabase Waits for the site's database to accept connections as it can take a bit longer to start than WordPress
+func (s *Site) waitForDatabase() error {
+
+	container := s.getContainerName("database")
+
+	// Connect over TCP as the database only listens on its socket while it is still initializing
+	pingCommand := []string{
+		fmt.Sprintf("mariadb-admin ping --silent --protocol=tcp -h 127.0.0.1 -u%s -p%s", databaseUser, databasePassword),
+	}
+
+	for tries := 0; tries < 30; tries++ {
+
+		_, isRunning := s.dockerClient.IsContainerRunning(container)
+		if isRunning {
+			output, err := s.dockerClient.ContainerExec(container, "", pingCommand)
+			if err == nil && output.ExitCode == 0 {
+				return nil
+			}
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+
+	return fmt.Errorf("timeout reached. unable to connect to the database")
+}
+
+// InstallUsers Creates any additional users set in the site config, skipping those that already exist
+func (s *Site) InstallUsers() error {
+
+	users, err := s.getSiteUsers()
+	if err != nil {
+		return err
+	}
+
+	if len(users) == 0 {
+		return nil
+	}
+
+	listCommand := []string{
+		"user",
+		"list",
+		"--field=user_login",
+		"--format=json",
+	}
+
+	commandOutput, err := s.RunWPCli(listCommand)
+	if err != nil {
+		return err
+	}
+
+	existingUsers := []string{}
+
+	err = json.Unmarshal([]byte(commandOutput), &existingUsers)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+
+		if appConfig.CheckString(user.Username, existingUsers) {
+			console.Warn("User %s already exists. Skipping.", user.Username)
+			continue
+		}
+
+		createCommand := []string{
+			"user",
+			"create",
+			user.Username,
+			user.Email,
+			fmt.Sprintf("--role=%s", user.Role),
+			fmt.Sprintf("--user_pass=%s", user.Password),
+		}
+
+		_, err = s.RunWPCli(createCommand)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InstallDefaultPlugins Installs and activates the plugins in the site config, skipping those that are already active
+func (s *Site) InstallDefaultPlugins() error {
+
+	plugins, err := s.GetPluginDetails()
+	if err != nil {
+		return err
+	}
+
+	pluginStatuses := make(map[string]string, len(plugins))
+	for _, plugin := range plugins {
+		pluginStatuses[plugin.Name] = plugin.Status
+	}
+
+	installed := []string{}
+	activated := []string{}
+
+	for _, plugin := range s.Settings.Plugins {
+
+		status, isInstalled := pluginStatuses[plugin]
+
+		// Restarting a site shouldn't download plugins it already has
+		if isInstalled && status != "inactive" {
+			continue
+		}
+
+		setupCommand := []string{
+			"plugin",
+			"install",
+			"--activate",
+			plugin,
+		}
+
+		if isInstalled {
+			setupCommand = []string{
+				"plugin",
+				"activate",
+				plugin,
+			}
+		}
+
+		statusCode, output, err := s.runWPCli(setupCommand, []mount.Mount{})
+		if err != nil {
+			return err
+		}
+
+		if statusCode != 0 {
+			console.Warn("Unable to install the %s plugin: %s", plugin, strings.TrimSpace(output))
+			continue
+		}
+
+		if isInstalled {
+			activated = append(activated, plugin)
+		} else {
+			installed = append(installed, plugin)
+		}
+	}
+
+	if len(installed) > 0 {
+		console.Info("Installed plugins: %s", strings.Join(installed, ", "))
+	}
+
+	if len(activated) > 0 {
+		console.Info("Activated plugins: %s", strings.Join(activated, ", "))
+	}
+
+	return nil
+}
+
+// InstallPlugin Installs and activates the given plugin and saves it to the site config so it's installed on future starts
+func (s *Site) InstallPlugin(plugin string) error {
+
+	statusCode, output, err := s.runWPCli([]string{"plugin", "install", "--activate", plugin}, []mount.Mount{})
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 0 {
+		return fmt.Errorf("unable to install the %s plugin: %s", plugin, strings.TrimSpace(output))
+	}
+
+	plugins := s.Settings.Plugins
+
+	if !appConfig.CheckString(plugin, plugins) {
+		s.setSetting("plugins", append(plugins, plugin))
+	}
+
+	return s.writeSiteConfig()
+}
+
+// RunWPCli Runs a wp-cli command returning it's output and any errors
+func (s *Site) RunWPCli(command []string) (string, error) {
+
+	_, output, err := s.runWPCli(command, []mount.Mount{})
+
+	return output, err
+}
+
+// RunWPCliAsUser Runs a wp-cli command as the given WordPress user, specified by ID, login or email, returning it's output and any errors
+func (s *Site) RunWPCliAsUser(command []string, user string) (string, error) {
+
+	err := s.checkUser(user)
+	if err != nil {
+		return "", err
+	}
+
+	return s.RunWPCli(append(command, fmt.Sprintf("--user=%s", user)))
+}
+
+// checkUser Returns an error if the given WordPress user, specified by ID, login or email, doesn't exist
+func (s *Site) checkUser(user string) error {
+
+	statusCode, _, err := s.runWPCli([]string{"user", "get", user, "--field=ID"}, []mount.Mount{})
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 0 {
+		return fmt.Errorf("the user %s doesn't exist on this site", user)
+	}
+
+	return nil
+}
+
+// EvalFile Runs the PHP file on the host with "wp eval-file" after loading WordPress, returning its output.
+// A file of "-" reads the PHP from the reader instead. Any extra arguments are passed to the file and the code runs as
+// the given user if one is set. Returns an error with the output if the PHP fails.
+func (s *Site) EvalFile(file string, args []string, user string, stdin io.Reader) (string, error) {
+
+	if len(user) > 0 {
+		err := s.checkUser(user)
+		if err != nil {
+			return "", err
+		}
+
+		args = append(args, fmt.Sprintf("--user=%s", user))
+	}
+
+	// The CLI container has no standard input so the code is saved to a file it can read
+	if file == "-" {
+		evalFile, err := os.CreateTemp("", "kana-eval-*.php")
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(evalFile.Name())
+
+		_, err = io.Copy(evalFile, stdin)
+
+		if closeErr := evalFile.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err == nil {
+			err = os.Chmod(evalFile.Name(), 0644)
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		file = evalFile.Name()
+	}
+
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(s.StaticConfig.WorkingDirectory, file)
+	}
+
+	fileInfo, err := os.Stat(file)
+	if err != nil || fileInfo.IsDir() {
+		return "", fmt.Errorf("the PHP file %s doesn't exist", file)
+	}
+
+	evalFile := path.Join("/tmp", "kana", filepath.Base(file))
+
+	evalMounts := []mount.Mount{
+		{
+			Type:     mount.TypeBind,
+			Source:   file,
+			Target:   evalFile,
+			ReadOnly: true,
+		},
+	}
+
+	statusCode, output, err := s.runWPCli(append([]string{"eval-file", evalFile}, args...), evalMounts)
+	if err != nil {
+		return output, err
+	}
+
+	if statusCode != 0 {
+		return output, fmt.Errorf("%s failed: %s", filepath.Base(file), strings.TrimSpace(output))
+	}
+
+	return output, nil
+}
+
+// RunRemoteWPCli Runs a wp-cli command against the site on the Docker host given, such as ssh://me@dev.example.com,
+// returning its exit code, output and any errors. The command shares the files of the remote WordPress container so it works
+// without knowing where the site is stored on the remote machine.
+func (s *Site) RunRemoteWPCli(host string, command []string) (int64, string, error) {
+
+	remoteClient, err := docker.NewRemoteController(host)
+	if err != nil {
+		return 1, "", err
+	}
+
+	wordPressContainer := s.getContainerName("wordpress")
+
+	if _, isRunning := remoteClient.IsContainerRunning(wordPressContainer); !isRunning {
+		return 1, "", fmt.Errorf("the site %s isn't running on %s. Make sure the site has the same name on the remote host", s.StaticConfig.SiteName, host)
+	}
+
+	fullCommand := []string{
+		"wp",
+		fmt.Sprintf("--path=%s", s.getWordPressPath()),
+	}
+
+	fullCommand = append(fullCommand, command...)
+
+	container := docker.ContainerConfig{
+		Name:        s.getContainerName("wordpress_cli"),
+		Image:       s.getCLIImage(),
+		NetworkName: s.getNetworkName(),
+		HostName:    s.getContainerName("wordpress_cli"),
+		Command:     fullCommand,
+		Env:         s.getDatabaseEnv(),
+		Labels: map[string]string{
+			appConfig.GetSiteLabel(s.DynamicConfig): s.StaticConfig.SiteName,
+		},
+		VolumesFrom: []string{wordPressContainer},
+	}
+
+	err = remoteClient.EnsureImage(container.Image)
+	if err != nil {
+		return 1, "", err
+	}
+
+	return remoteClient.ContainerRunAndClean(container)
+}
+
+// getCLIImage Returns the image used to run wp-cli commands, which uses the same PHP version as the site
+func (s *Site) getCLIImage() string {
+	return appConfig.GetImage(s.DynamicConfig, "cli", s.getRunningPHPVersion())
+}
+
+// getRunningPHPVersion Returns the PHP version the site's WordPress container was started with or, if the site isn't
+// running, the version in the site's "php" option
+func (s *Site) getRunningPHPVersion() string {
+
+	labels := s.dockerClient.ContainerGetLabels(s.getContainerName("wordpress"))
+
+	if phpVersion, ok := labels[appConfig.GetPHPLabel(s.DynamicConfig)]; ok && len(phpVersion) > 0 {
+		return phpVersion
+	}
+
+	return s.Settings.PHP
+}
+
+// runWPCli Runs a wp-cli command with any extra mounts it needs, returning the command's exit code and output
+func (s *Site) runWPCli(command []string, extraMounts []mount.Mount) (int64, string, error) {
+
+	container, err := s.getWPCliContainer(command, extraMounts)
+	if err != nil {
+		return 1, "", err
+	}
+
+	return s.dockerClient.ContainerRunAndClean(container)
+}
+
+// runWPCliAndFollow Runs a wp-cli command, copying its output to the writer as it is printed, and returns its exit code
+func (s *Site) runWPCliAndFollow(command []string, writer io.Writer) (int64, error) {
+
+	container, err := s.getWPCliContainer(command, []mount.Mount{})
+	if err != nil {
+		return 1, err
+	}
+
+	return s.dockerClient.ContainerRunAndFollow(container, writer)
+}
+
+// getWPCliContainer Returns the config of the container that runs the wp-cli command, making sure its network and
+// image are ready
+func (s *Site) getWPCliContainer(command []string, extraMounts []mount.Mount) (docker.ContainerConfig, error) {
+
+	_, _, err := s.dockerClient.EnsureNetworkWithOptions(s.getNetworkName(), traefik.GetNetworkOptions(s.DynamicConfig))
+	if err != nil {
+		return docker.ContainerConfig{}, err
+	}
+
+	siteDir := path.Join(s.StaticConfig.AppDirectory, "sites", s.StaticConfig.SiteName)
+	appDir := path.Join(siteDir, "app")
+	runningConfig := s.GetRunningConfig()
+
+	if runningConfig.Local {
+		appDir, err = s.getLocalAppDir()
+		if err != nil {
+			return docker.ContainerConfig{}, err
+		}
+	}
+
+	appVolumes, err := s.getMounts(appDir, runningConfig.Type)
+	if err != nil {
+		return docker.ContainerConfig{}, err
+	}
+
+	appVolumes = append(appVolumes, extraMounts...)
+
+	fullCommand := []string{
+		"wp",
+		fmt.Sprintf("--path=%s", s.getWordPressPath()),
+	}
+
+	fullCommand = append(fullCommand, command...)
+
+	container := docker.ContainerConfig{
+		Name:        s.getContainerName("wordpress_cli"),
+		Image:       s.getCLIImage(),
+		NetworkName: s.getNetworkName(),
+		HostName:    s.getContainerName("wordpress_cli"),
+		Command:     fullCommand,
+		Env:         append(s.getDatabaseEnv(), appConfig.GetProxyEnv(s.DynamicConfig)...),
+		Labels: map[string]string{
+			appConfig.GetSiteLabel(s.DynamicConfig): s.StaticConfig.SiteName,
+		},
+		Volumes: appVolumes,
+		User:    s.getContainerUser(),
+	}
+
+	return container, s.dockerClient.EnsureImage(container.Image)
+}
+
+const coreTablesQuery = "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND (TABLE_NAME LIKE '%options' OR TABLE_NAME LIKE '%usermeta')"
+
+// checkTablePrefix Returns an error if WordPress is already installed in the site's database with a different table
+// prefix, such as after the "tablePrefix" option is changed, so a new empty site isn't installed next to it
+func (s *Site) checkTablePrefix() error {
+
+	results, err := s.QueryDatabase(coreTablesQuery)
+	if err != nil {
+		return err
+	}
+
+	tables := []string{}
+
+	for _, row := range results[0].Rows {
+		if len(row) > 0 {
+			tables = append(tables, row[0])
+		}
+	}
+
+	otherPrefixes := getOtherTablePrefixes(tables, s.Settings.TablePrefix)
+	if len(otherPrefixes) > 0 {
+		return fmt.Errorf("WordPress is already installed in the site's database with the table prefix %q. Please set the \"tablePrefix\" option back to it, or run 'kana destroy' to start over with %q", otherPrefixes[0], s.Settings.TablePrefix)
+	}
+
+	return nil
+}
+
+// getOtherTablePrefixes Returns the prefixes other than the given one that have both an options and a usermeta table,
+// which every WordPress install has. Plugin tables ending in "options" and multisite sub-sites, which share the main
+// site's usermeta table, aren't mistaken for another install.
+func getOtherTablePrefixes(tables []string, tablePrefix string) []string {
+
+	optionsPrefixes := map[string]bool{}
+
+	for _, table := range tables {
+		if strings.HasSuffix(table, "options") {
+			optionsPrefixes[strings.TrimSuffix(table, "options")] = true
+		}
+	}
+
+	prefixes := []string{}
+
+	for _, table := range tables {
+
+		prefix := strings.TrimSuffix(table, "usermeta")
+		if prefix != table && prefix != tablePrefix && optionsPrefixes[prefix] {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	return prefixes
+}
+
+// IsWordPressInstalled Checks if WordPress has been installed in the site's database, not just that the site responds
+func (s *Site) IsWordPressInstalled() (bool, error) {
+
+	isInstalledCommand := []string{
+		"core",
+		"is-installed",
+	}
+
+	statusCode, _, err := s.runWPCli(isInstalledCommand, []mount.Mount{})
+	if err != nil {
+		return false, err
+	}
+
+	return statusCode == 0, nil
+}
+
+// GetInstalledWordPressPlugins Returns a list of the plugins that have been installed on the site
+func (s *Site) GetInstalledWordPressPlugins() ([]string, error) {
+
+	rawPlugins, err := s.GetPluginDetails()
+	if err != nil {
+		return []string{}, err
+	}
+
+	plugins := []string{}
+
+	// Plugins mounted from the project aren't installed from WordPress.org
+	mountedPlugins := []string{}
+	if s.Settings.Type == "plugins" {
+		mountedPlugins = s.getExtensionNames()
+	}
+
+	for _, plugin := range rawPlugins {
+
+		if plugin.Status != "dropin" && plugin.Name != s.StaticConfig.SiteName && plugin.Name != "hello" && plugin.Name != "akismet" && !appConfig.CheckString(plugin.Name, mountedPlugins) {
+			plugins = append(plugins, plugin.Name)
+		}
+	}
+
+	return plugins, nil
+}
+
+// GetPluginDetails Returns the name, status, available update and version of every plugin on the site
+func (s *Site) GetPluginDetails() ([]PluginInfo, error) {
+
+	commands := []string{
+		"plugin",
+		"list",
+		"--format=json",
+	}
+
+	plugins := []PluginInfo{}
+
+	commandOutput, err := s.RunWPCli(commands)
+	if err != nil {
+		return plugins, err
+	}
+
+	err = json.Unmarshal([]byte(commandOutput), &plugins)
+
+	return plugins, err
 }
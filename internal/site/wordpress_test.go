@@ -0,0 +1,98 @@
+package site
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+
+	"github.com/spf13/viper"
+)
+
+func TestGetAdminURL(t *testing.T) {
+
+	tests := []struct {
+		subdirectory string
+		url          string
+	}{
+		{"", "https://test.sites.kana.li/wp-admin/"},
+		{"wp", "https://test.sites.kana.li/wp/wp-admin/"},
+	}
+
+	for _, test := range tests {
+
+		site := &Site{
+			StaticConfig: appConfig.StaticConfig{AppDomain: "sites.kana.li"},
+		}
+
+		site.setSiteName("test")
+		site.Settings.Subdirectory = test.subdirectory
+
+		url := site.getAdminURL()
+
+		if url != test.url {
+			t.Errorf("getAdminURL() with subdirectory %q returned %q. Expected %q", test.subdirectory, url, test.url)
+		}
+	}
+}
+
+func TestGetWordPressLabelsBasicAuth(t *testing.T) {
+
+	site := &Site{
+		StaticConfig:  appConfig.StaticConfig{AppDomain: "sites.kana.li"},
+		DynamicConfig: viper.New(),
+	}
+
+	site.setSiteName("test")
+	site.Settings.Traefik.BasicAuth = []string{"user:$apr1$hash"}
+
+	for _, insecure := range []bool{false, true} {
+
+		site.Settings.Insecure = insecure
+
+		for routerName, domain := range map[string]string{
+			"wordpress-test":       "test.sites.kana.li",
+			"wordpress-test-php81": site.getPHPVersionDomain("8.1"),
+		} {
+
+			labels := site.getWordPressLabels(routerName, domain)
+
+			for label := range labels {
+
+				if !strings.HasSuffix(label, ".rule") {
+					continue
+				}
+
+				router := strings.TrimSuffix(label, ".rule")
+
+				if !strings.Contains(labels[router+".middlewares"], "wordpress-test-auth@docker") {
+					t.Errorf("The router %s isn't protected by basic auth (insecure: %t). Middlewares: %q", router, insecure, labels[router+".middlewares"])
+				}
+			}
+		}
+	}
+}
+
+func TestGetOtherTablePrefixes(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		tables   []string
+		expected []string
+	}{
+		{"empty database", []string{}, []string{}},
+		{"same prefix", []string{"wp_options", "wp_usermeta"}, []string{}},
+		{"changed prefix", []string{"wp_custom_options", "wp_custom_usermeta"}, []string{"wp_custom_"}},
+		{"multisite", []string{"wp_options", "wp_usermeta", "wp_2_options"}, []string{}},
+		{"plugin table", []string{"wp_plugin_options"}, []string{}},
+	}
+
+	for _, test := range tests {
+
+		prefixes := getOtherTablePrefixes(test.tables, "wp_")
+
+		if strings.Join(prefixes, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%s: expected %v but got %v", test.name, test.expected, prefixes)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+var validXdebugModes = []string{
+	"debug",
+	"profile",
+	"debug,profile",
+}
+
+// xdebugOutputTarget Is where Xdebug writes its profiles inside the WordPress container
+const xdebugOutputTarget = "/tmp/xdebug"
+
+// xdebugModeFile Is loaded after php.ini so its settings replace the defaults installXdebug adds there
+const xdebugModeFile = "/usr/local/etc/php/conf.d/zz-kana-xdebug.ini"
+
+// getXdebugOutputDirectory Returns the absolute path of the folder in the site's "xdebugOutputDir" option, relative to
+// the site's folder, or the xdebug folder in the site's directory if it isn't set
+func (s *Site) getXdebugOutputDirectory() string {
+
+	outputDirectory := s.Settings.XdebugOutputDir
+
+	if len(outputDirectory) == 0 {
+		return filepath.Join(s.StaticConfig.SiteDirectory, "xdebug")
+	}
+
+	if !filepath.IsAbs(outputDirectory) {
+		outputDirectory = filepath.Join(s.StaticConfig.WorkingDirectory, outputDirectory)
+	}
+
+	return outputDirectory
+}
+
+// getXdebugMount Returns the mount for the folder Xdebug writes its profiles to, creating the folder if needed.
+// Returns false if the "xdebug" option isn't set.
+func (s *Site) getXdebugMount() (mount.Mount, bool, error) {
+
+	if !s.Settings.Xdebug {
+		return mount.Mount{}, false, nil
+	}
+
+	outputDirectory := s.getXdebugOutputDirectory()
+
+	// shareXdebugOutput lets PHP write to the folder once the container is running
+	err := os.MkdirAll(outputDirectory, 0755)
+	if err != nil {
+		return mount.Mount{}, false, err
+	}
+
+	return mount.Mount{
+		Type:   mount.TypeBind,
+		Source: outputDirectory,
+		Target: xdebugOutputTarget,
+	}, true, nil
+}
+
+// shareXdebugOutput Gives PHP, which runs as www-data unless the "hostUser" option is on, write access to the folder
+// Xdebug writes its profiles to. The folder keeps the host user's group so they can still remove the profiles, without
+// making it writable by every user on the system. Docker Desktop already lets containers write to shared folders.
+func (s *Site) shareXdebugOutput() error {
+
+	if runtime.GOOS != "linux" || len(s.getContainerUser()) > 0 {
+		return nil
+	}
+
+	output, err := s.runCli(fmt.Sprintf("chown www-data:%d %s && chmod 2775 %s", os.Getgid(), xdebugOutputTarget, xdebugOutputTarget), false)
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to let PHP write to the Xdebug output folder: %s", strings.TrimSpace(output.StdErr))
+	}
+
+	return nil
+}
+
+// writeXdebugMode Saves the Xdebug mode in the "xdebugMode" option to the site's WordPress container without restarting it
+func (s *Site) writeXdebugMode() error {
+
+	mode := s.Settings.XdebugMode
+	if !appConfig.CheckString(mode, validXdebugModes) {
+		mode = "debug"
+	}
+
+	settings := []string{
+		fmt.Sprintf("xdebug.mode=%s", mode),
+		fmt.Sprintf("xdebug.output_dir=%s", xdebugOutputTarget),
+		"xdebug.profiler_output_name=cachegrind.out.%t.%p",
+	}
+
+	output, err := s.runCli(fmt.Sprintf("printf '%%s\\n' '%s' > %s", strings.Join(settings, "' '"), xdebugModeFile), false)
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to set the Xdebug mode: %s", strings.TrimSpace(output.StdErr))
+	}
+
+	return nil
+}
+
+// SetXdebugProfiling Turns the Xdebug profiler on or off for the running site, saving the mode to the "xdebugMode"
+// option so it is used the next time the site starts. Returns the folder the profiles are written to.
+func (s *Site) SetXdebugProfiling(enable bool) (string, error) {
+
+	hasOutputMount := false
+
+	for _, containerMount := range s.dockerClient.ContainerGetMounts(s.getContainerName("wordpress")) {
+		if containerMount.Destination == xdebugOutputTarget {
+			hasOutputMount = true
+		}
+	}
+
+	if !hasOutputMount {
+		return "", fmt.Errorf("xdebug isn't enabled on the site. Please stop the site and start it again with 'kana start --xdebug'")
+	}
+
+	modes := []string{}
+
+	for _, mode := range strings.Split(s.Settings.XdebugMode, ",") {
+		if mode != "profile" && len(mode) > 0 {
+			modes = append(modes, mode)
+		}
+	}
+
+	if enable {
+		modes = append(modes, "profile")
+	}
+
+	// Xdebug needs at least one mode so turning off the profiler falls back to step debugging
+	if len(modes) == 0 {
+		modes = append(modes, "debug")
+	}
+
+	s.setSetting("xdebugMode", strings.Join(modes, ","))
+
+	err := s.writeSiteConfig()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.writeXdebugMode()
+	if err != nil {
+		return "", err
+	}
+
+	return s.getXdebugOutputDirectory(), s.restartWordPress()
+}
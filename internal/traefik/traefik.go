@@ -1,24 +1,32 @@
 package traefik
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"path"
+	"regexp"
 
 	"github.com/ChrisWiegman/kana-cli/internal/appConfig"
 	"github.com/ChrisWiegman/kana-cli/internal/appSetup"
 	"github.com/ChrisWiegman/kana-cli/internal/docker"
 
 	"github.com/docker/docker/api/types/mount"
+	"github.com/spf13/viper"
 )
 
-var traefikContainerName = "kana_traefik"
+// logLevelDefinition Matches the log level in Traefik's static config file
+var logLevelDefinition = regexp.MustCompile(`(?m)^level = ".*"$`)
 
 type Traefik struct {
-	dockerClient docker.DockerClient
-	appDirectory string
+	dockerClient  docker.DockerClient
+	appDirectory  string
+	appDomain     string
+	dynamicConfig *viper.Viper
 }
 
 // NewTraefik Setup a new traefik object for controlling the traefik container
-func NewTraefik(staticConfig appConfig.StaticConfig) (*Traefik, error) {
+func NewTraefik(staticConfig appConfig.StaticConfig, dynamicConfig *viper.Viper) (*Traefik, error) {
 
 	t := new(Traefik)
 
@@ -33,6 +41,8 @@ func NewTraefik(staticConfig appConfig.StaticConfig) (*Traefik, error) {
 	}
 
 	t.appDirectory = staticConfig.AppDirectory
+	t.appDomain = staticConfig.AppDomain
+	t.dynamicConfig = dynamicConfig
 	t.dockerClient = *dockerClient
 
 	return t, nil
@@ -41,35 +51,41 @@ func NewTraefik(staticConfig appConfig.StaticConfig) (*Traefik, error) {
 // StartTraefik starts the Traefik container
 func (t *Traefik) StartTraefik() error {
 
-	_, _, err := t.dockerClient.EnsureNetwork("kana")
+	prefix := appConfig.GetPrefix(t.dynamicConfig)
+
+	_, _, err := t.dockerClient.EnsureNetworkWithOptions(prefix, GetNetworkOptions(t.dynamicConfig))
+	if err != nil {
+		return err
+	}
+
+	traefikImage := appConfig.GetImage(t.dynamicConfig, "traefik", "")
+
+	err = t.dockerClient.EnsureImage(traefikImage)
 	if err != nil {
 		return err
 	}
 
-	err = t.dockerClient.EnsureImage("traefik")
+	staticConfigFile, err := t.getStaticConfigFile()
 	if err != nil {
 		return err
 	}
 
 	traefikPorts := []docker.ExposedPorts{
-		{Port: "80", Protocol: "tcp"},
-		{Port: "443", Protocol: "tcp"},
-		{Port: "8080", Protocol: "tcp"},
+		{Port: "80", Protocol: "tcp", HostIP: t.dynamicConfig.GetString("network.hostIP")},
+		{Port: "443", Protocol: "tcp", HostIP: t.dynamicConfig.GetString("network.hostIP")},
 	}
 
 	traefikConfig := docker.ContainerConfig{
-		Name:        traefikContainerName,
-		Image:       "traefik",
+		Name:        t.getContainerName(),
+		Image:       traefikImage,
 		Ports:       traefikPorts,
-		NetworkName: "kana",
-		HostName:    "kanatraefik",
-		Labels: map[string]string{
-			"kana.global": "true",
-		},
+		NetworkName: prefix,
+		HostName:    fmt.Sprintf("%straefik", prefix),
+		Labels:      t.getLabels(),
 		Volumes: []mount.Mount{
 			{
 				Type:   mount.TypeBind,
-				Source: path.Join(t.appDirectory, "config", "traefik", "traefik.toml"),
+				Source: staticConfigFile,
 				Target: "/etc/traefik/traefik.toml",
 			},
 			{
@@ -84,7 +100,7 @@ func (t *Traefik) StartTraefik() error {
 			},
 			{
 				Type:   mount.TypeBind,
-				Source: "/var/run/docker.sock",
+				Source: t.dockerClient.GetSocketPath(),
 				Target: "/var/run/docker.sock",
 			},
 		},
@@ -95,10 +111,95 @@ func (t *Traefik) StartTraefik() error {
 	return err
 }
 
+// getStaticConfigFile Returns Traefik's static config file. When the log settings are changed or access logs are enabled
+// a copy of the file with those changes is used as Traefik can't combine a config file with command line options.
+func (t *Traefik) getStaticConfigFile() (string, error) {
+
+	configFile := path.Join(t.appDirectory, "config", "traefik", "traefik.toml")
+
+	logLevel := t.dynamicConfig.GetString("traefik.logLevel")
+	logFormat := t.dynamicConfig.GetString("traefik.logFormat")
+	accessLog := t.dynamicConfig.GetBool("traefik.accessLog")
+
+	if logLevel == "INFO" && logFormat == "common" && !accessLog {
+		return configFile, nil
+	}
+
+	staticConfig, err := os.ReadFile(configFile)
+	if err != nil {
+		return "", err
+	}
+
+	customConfig := logLevelDefinition.ReplaceAllLiteralString(string(staticConfig), fmt.Sprintf("level = %q\nformat = %q", logLevel, logFormat))
+
+	if accessLog {
+		customConfig += fmt.Sprintf("\n[accessLog]\nformat = %q\n", logFormat)
+	}
+
+	customConfigFile := path.Join(t.appDirectory, "config", "traefik", "traefik-custom.toml")
+
+	err = os.WriteFile(customConfigFile, []byte(customConfig), 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return customConfigFile, nil
+}
+
+// FollowLogs Copies the logs of the Traefik container to the writer, continuing until the container stops if follow is set
+func (t *Traefik) FollowLogs(follow bool, writer io.Writer) error {
+	return t.dockerClient.ContainerLogFollow(t.getContainerName(), follow, writer)
+}
+
+// getContainerName Returns the name of the Traefik container
+func (t *Traefik) getContainerName() string {
+	return fmt.Sprintf("%s_traefik", appConfig.GetPrefix(t.dynamicConfig))
+}
+
+// GetNetworkOptions Returns the options used to create the network shared by all sites
+func GetNetworkOptions(dynamicConfig *viper.Viper) docker.NetworkOptions {
+	prefix := appConfig.GetPrefix(dynamicConfig)
+
+	return docker.NetworkOptions{
+		EnableIPv6:      dynamicConfig.GetBool("network.ipv6"),
+		IPv6Subnet:      dynamicConfig.GetString("network.ipv6Subnet"),
+		Subnet:          dynamicConfig.GetString("network.subnet"),
+		Gateway:         dynamicConfig.GetString("network.gateway"),
+		ManagedLabel:    fmt.Sprintf("%s.managed", prefix),
+		ContainerPrefix: fmt.Sprintf("%s_", prefix),
+	}
+}
+
+// getLabels Returns the labels for the Traefik container, adding the dashboard routers if the dashboard is enabled
+func (t *Traefik) getLabels() map[string]string {
+
+	labels := map[string]string{
+		fmt.Sprintf("%s.global", appConfig.GetPrefix(t.dynamicConfig)): "true",
+	}
+
+	if !t.dynamicConfig.GetBool("traefik.dashboard") {
+		return labels
+	}
+
+	dashboardRule := fmt.Sprintf("Host(`traefik.%s`)", t.appDomain)
+
+	labels["traefik.enable"] = "true"
+	labels["traefik.http.routers.kana-traefik-http.entrypoints"] = "web"
+	labels["traefik.http.routers.kana-traefik-http.rule"] = dashboardRule
+	labels["traefik.http.routers.kana-traefik-http.middlewares"] = "redirect-to-https@file"
+	labels["traefik.http.routers.kana-traefik-http.service"] = "api@internal"
+	labels["traefik.http.routers.kana-traefik.entrypoints"] = "websecure"
+	labels["traefik.http.routers.kana-traefik.rule"] = dashboardRule
+	labels["traefik.http.routers.kana-traefik.service"] = "api@internal"
+	labels["traefik.http.routers.kana-traefik.tls"] = "true"
+
+	return labels
+}
+
 // MaybeStopTraefik Checks to see if other sites are running and shuts down the traefik instance if none are
 func (t *Traefik) MaybeStopTraefik() error {
 
-	containers, err := t.dockerClient.ListContainers("")
+	containers, err := t.dockerClient.ListContainers(appConfig.GetSiteLabel(t.dynamicConfig), "")
 	if err != nil {
 		return err
 	}
@@ -110,16 +211,27 @@ func (t *Traefik) MaybeStopTraefik() error {
 	return nil
 }
 
+// RestartTraefik Recreates the Traefik container so it picks up any changes to its config
+func (t *Traefik) RestartTraefik() error {
+
+	_, err := t.dockerClient.ContainerStop(t.getContainerName())
+	if err != nil {
+		return err
+	}
+
+	return t.StartTraefik()
+}
+
 // Stops the Traefik container
 func (t *Traefik) StopTraefik() error {
 
-	_, err := t.dockerClient.ContainerStop(traefikContainerName)
+	_, err := t.dockerClient.ContainerStop(t.getContainerName())
 	if err != nil {
 		return err
 	}
 
-	// Delete the "kana" network as well
-	_, err = t.dockerClient.RemoveNetwork("kana")
+	// Delete the shared network as well
+	_, err = t.dockerClient.RemoveNetwork(appConfig.GetPrefix(t.dynamicConfig))
 
 	return err
 }